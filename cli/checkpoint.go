@@ -0,0 +1,181 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NethermindEth/sedge/configs"
+)
+
+// checkpointSyncDisabled is the sentinel value for --checkpoint-sync=disable.
+const checkpointSyncDisabled = "disable"
+
+// checkpointSyncAuto is the sentinel value for --checkpoint-sync-url=auto.
+const checkpointSyncAuto = "auto"
+
+// maxCheckpointSlotLag is how many slots behind the majority finalized slot
+// a candidate checkpoint endpoint is allowed to be before it's discarded.
+const maxCheckpointSlotLag = 32
+
+type checkpointCandidate struct {
+	URL     string
+	Slot    uint64
+	Latency time.Duration
+}
+
+// finalizedHeaderResponse is the subset of the beacon API's
+// /eth/v1/beacon/headers/finalized response sedge cares about.
+type finalizedHeaderResponse struct {
+	Data struct {
+		Header struct {
+			Message struct {
+				Slot string `json:"slot"`
+			} `json:"message"`
+		} `json:"header"`
+	} `json:"data"`
+}
+
+// resolveCheckpointSyncUrl expands --checkpoint-sync-url into a concrete
+// URL (or empty string if checkpoint sync should be skipped/disabled).
+//
+// Accepted forms:
+//   - "" (unset): no checkpoint sync
+//   - "disable": treated like ""; the consensus client's disable flag is only
+//     rendered for --checkpoint-sync=disable, via checkpointSyncDisableFlag
+//   - "auto": probe every well-known endpoint for network and pick the best
+//   - a single URL: used as-is
+//   - a comma-separated list: probed like "auto", but restricted to the given candidates
+func resolveCheckpointSyncUrl(network, rawUrl string) (string, error) {
+	if rawUrl == "" || rawUrl == checkpointSyncDisabled {
+		return "", nil
+	}
+
+	var candidates []string
+	switch {
+	case rawUrl == checkpointSyncAuto:
+		netCfg, ok := configs.NetworksConfigs[network]
+		if !ok || len(netCfg.CheckpointSyncEndpoints) == 0 {
+			return "", fmt.Errorf(configs.NoCheckpointEndpointsError, network)
+		}
+		candidates = netCfg.CheckpointSyncEndpoints
+	case strings.Contains(rawUrl, ","):
+		candidates = strings.Split(rawUrl, ",")
+	default:
+		return rawUrl, nil
+	}
+
+	best, rejected, err := pickFreshestCheckpoint(candidates)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range rejected {
+		log.Warnf(configs.CheckpointEndpointRejectedWarning, r)
+	}
+	return best, nil
+}
+
+// pickFreshestCheckpoint probes each candidate's
+// /eth/v1/beacon/headers/finalized endpoint, discards any endpoint whose
+// reported slot is more than maxCheckpointSlotLag behind the majority, and
+// returns the fastest of the remaining endpoints.
+func pickFreshestCheckpoint(candidates []string) (best string, rejected []string, err error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	probed := make([]checkpointCandidate, 0, len(candidates))
+
+	for _, url := range candidates {
+		url = strings.TrimSpace(url)
+		start := time.Now()
+		resp, reqErr := client.Get(strings.TrimRight(url, "/") + "/eth/v1/beacon/headers/finalized")
+		latency := time.Since(start)
+		if reqErr != nil {
+			rejected = append(rejected, url)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			rejected = append(rejected, url)
+			continue
+		}
+		defer resp.Body.Close()
+
+		var parsed finalizedHeaderResponse
+		if decodeErr := json.NewDecoder(resp.Body).Decode(&parsed); decodeErr != nil {
+			rejected = append(rejected, url)
+			continue
+		}
+		slot, parseErr := strconv.ParseUint(parsed.Data.Header.Message.Slot, 10, 64)
+		if parseErr != nil {
+			rejected = append(rejected, url)
+			continue
+		}
+		probed = append(probed, checkpointCandidate{URL: url, Slot: slot, Latency: latency})
+	}
+
+	if len(probed) == 0 {
+		return "", rejected, fmt.Errorf(configs.NoLiveCheckpointEndpointsError)
+	}
+
+	// The majority slot is the one reported by the most candidates; ties go
+	// to the higher slot.
+	counts := make(map[uint64]int, len(probed))
+	var majoritySlot uint64
+	var majorityCount int
+	for _, c := range probed {
+		counts[c.Slot]++
+		if n := counts[c.Slot]; n > majorityCount || (n == majorityCount && c.Slot > majoritySlot) {
+			majoritySlot, majorityCount = c.Slot, n
+		}
+	}
+
+	fresh := make([]checkpointCandidate, 0, len(probed))
+	for _, c := range probed {
+		if c.Slot < majoritySlot && majoritySlot-c.Slot > maxCheckpointSlotLag {
+			rejected = append(rejected, c.URL)
+			continue
+		}
+		fresh = append(fresh, c)
+	}
+
+	best = fresh[0].URL
+	bestLatency := fresh[0].Latency
+	for _, c := range fresh[1:] {
+		if c.Latency < bestLatency {
+			best, bestLatency = c.URL, c.Latency
+		}
+	}
+	return best, rejected, nil
+}
+
+// checkpointSyncDisableFlag returns the consensus-client-specific flag that
+// explicitly turns checkpoint sync off, since not every client needs one:
+// lighthouse simply omits --checkpoint-sync-url, while caplin requires an
+// explicit disable flag.
+func checkpointSyncDisableFlag(consensusClient string) string {
+	switch consensusClient {
+	case "erigon":
+		return "--caplin.checkpoint-sync.disable=true"
+	default:
+		return ""
+	}
+}
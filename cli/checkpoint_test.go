@@ -0,0 +1,73 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// finalizedAt returns a test server that answers
+// /eth/v1/beacon/headers/finalized with the given slot.
+func finalizedAt(slot uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"header":{"message":{"slot":"%d"}}}}`, slot)
+	}))
+}
+
+func TestPickFreshestCheckpoint(t *testing.T) {
+	fresh := finalizedAt(1000)
+	defer fresh.Close()
+	stale := finalizedAt(900) // more than maxCheckpointSlotLag behind fresh
+	defer stale.Close()
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer dead.Close()
+
+	best, rejected, err := pickFreshestCheckpoint([]string{fresh.URL, stale.URL, dead.URL})
+	if err != nil {
+		t.Fatalf("pickFreshestCheckpoint() error = %v", err)
+	}
+	if best != fresh.URL {
+		t.Errorf("pickFreshestCheckpoint() best = %q, want %q", best, fresh.URL)
+	}
+	if len(rejected) != 2 {
+		t.Errorf("pickFreshestCheckpoint() rejected = %v, want 2 entries", rejected)
+	}
+}
+
+func TestPickFreshestCheckpoint_NoneLive(t *testing.T) {
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer dead.Close()
+
+	if _, _, err := pickFreshestCheckpoint([]string{dead.URL}); err == nil {
+		t.Error("pickFreshestCheckpoint() error = nil, want an error when no candidate is live")
+	}
+}
+
+func TestCheckpointSyncDisableFlag(t *testing.T) {
+	if got := checkpointSyncDisableFlag("erigon"); got == "" {
+		t.Error("checkpointSyncDisableFlag(\"erigon\") = \"\", want a non-empty flag")
+	}
+	if got := checkpointSyncDisableFlag("lighthouse"); got != "" {
+		t.Errorf("checkpointSyncDisableFlag(\"lighthouse\") = %q, want \"\"", got)
+	}
+}
@@ -37,31 +37,44 @@ import (
 )
 
 var (
-	executionName     string
-	executionImage    string
-	consensusName     string
-	consensusImage    string
-	validatorName     string
-	validatorImage    string
-	generationPath    string
-	checkpointSyncUrl string
-	network           string
-	feeRecipient      string
-	jwtPath           string
-	graffiti          string
-	mevImage          string
-	install           bool
-	run               bool
-	y                 bool
-	services          *[]string
-	fallbackEL        *[]string
-	elExtraFlags      *[]string
-	clExtraFlags      *[]string
-	vlExtraFlags      *[]string
-	mapAllPorts       bool
-	noMev             bool
-	noValidator       bool
-	loggingFlag       string
+	executionName         string
+	executionImage        string
+	consensusName         string
+	consensusImage        string
+	validatorName         string
+	validatorImage        string
+	generationPath        string
+	checkpointSyncUrl     string
+	network               string
+	feeRecipient          string
+	jwtPath               string
+	keystorePath          string
+	graffiti              string
+	mevImage              string
+	install               bool
+	run                   bool
+	y                     bool
+	services              *[]string
+	fallbackEL            *[]string
+	elExtraFlags          *[]string
+	clExtraFlags          *[]string
+	vlExtraFlags          *[]string
+	mapAllPorts           bool
+	noMev                 bool
+	noValidator           bool
+	loggingFlag           string
+	configFilePath        string
+	printConfig           bool
+	mevRelayFlags         *[]string
+	mevProfile            string
+	checkpointSync        string
+	profileName           string
+	dockerNetworkName     string
+	dockerNetworkDriver   string
+	dockerNetworkSubnet   string
+	dockerNetworkGateway  string
+	dockerNetworkExternal bool
+	exposedPorts          *[]string
 )
 
 const (
@@ -100,6 +113,14 @@ Finally, it will run the generated docker-compose script. Only execution and con
 }
 
 func preRunCliCmd(cmd *cobra.Command, args []string) error {
+	// Resolve the effective configuration: defaults < config file < profile <
+	// environment variables (SEDGE_* prefix) < flags. See resolveCliConfig.
+	merged, err := resolveCliConfig(cmd)
+	if err != nil {
+		return err
+	}
+	applyCliConfig(merged)
+
 	// Quick run
 	if y {
 		install, run = true, true
@@ -167,6 +188,26 @@ func preRunCliCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if checkpointSync != "" && checkpointSync != checkpointSyncDisabled {
+		return fmt.Errorf(configs.InvalidCheckpointSyncFlagError, checkpointSync, checkpointSyncDisabled)
+	}
+
+	if !dockerNetworkExternal {
+		if err := validateDockerNetwork(cmd.Context(), dockerNetworkSubnet, dockerNetworkGateway); err != nil {
+			return err
+		}
+	}
+
+	// --print-config prints the loaded document only after it has passed the
+	// same validation as a normal run, so it can never show a config that
+	// wouldn't actually work.
+	if printConfig {
+		if err := printEffectiveConfig(cmd, merged); err != nil {
+			return err
+		}
+		os.Exit(0)
+	}
+
 	return nil
 }
 
@@ -181,7 +222,17 @@ func runCliCmd(cmd *cobra.Command, args []string) []error {
 		log.Warn(configs.MapAllPortsWarning)
 	}
 
-	// Warn if checkpoint url used
+	// Resolve auto/list/disable forms of --checkpoint-sync-url into a single
+	// concrete URL (or none), probing candidates for liveness and freshness.
+	if checkpointSync == checkpointSyncDisabled {
+		checkpointSyncUrl = ""
+	} else {
+		resolved, err := resolveCheckpointSyncUrl(network, checkpointSyncUrl)
+		if err != nil {
+			return []error{err}
+		}
+		checkpointSyncUrl = resolved
+	}
 	if checkpointSyncUrl != "" {
 		log.Warnf(configs.CheckpointUrlUsedWarning, checkpointSyncUrl)
 	}
@@ -242,25 +293,76 @@ func runCliCmd(cmd *cobra.Command, args []string) []error {
 	combinedClients.Validator.Image = validatorImage
 	combinedClients.Validator.Omited = noValidator
 
+	var checkpointSyncDisableCL string
+	if checkpointSync == checkpointSyncDisabled {
+		checkpointSyncDisableCL = checkpointSyncDisableFlag(combinedClients.Consensus.Name)
+	}
+
+	networkConfig, err := buildNetworkConfig(dockerNetworkName, dockerNetworkDriver, dockerNetworkSubnet, dockerNetworkGateway, dockerNetworkExternal, *exposedPorts)
+	if err != nil {
+		return []error{err}
+	}
+
+	// Resolve the mev-boost relay set from --mev-relay/--mev-profile (falling
+	// back to defaultMevProfile when neither is given), dropping any relay
+	// that fails its /eth/v1/builder/status health check.
+	useMev := !noMev && !noValidator
+	var relayURLs, builderFlag string
+	var minBid float64
+	if useMev {
+		explicitRelays := make([]mevRelay, 0, len(*mevRelayFlags))
+		for _, raw := range *mevRelayFlags {
+			relay, err := parseMevRelayFlag(raw)
+			if err != nil {
+				return []error{err}
+			}
+			explicitRelays = append(explicitRelays, relay)
+		}
+		relays, err := resolveMevRelays(network, mevProfile, explicitRelays)
+		if err != nil {
+			return []error{err}
+		}
+		if len(relays) == 0 {
+			// No relays resolved: either nothing was configured and the
+			// network doesn't support mev-boost, or the chosen profile has
+			// no relays for this network. Quietly skip mev-boost instead of
+			// warning about relays that were never there to be unhealthy.
+			useMev = false
+		} else if healthy := healthCheckMevRelays(relays); len(healthy) == 0 {
+			log.Warn(configs.NoHealthyMevRelaysWarning)
+			useMev = false
+		} else {
+			relayURLs = mevRelayURLs(healthy)
+			builderFlag = validatorBuilderFlag(combinedClients.Consensus.Name)
+			minBid = mevMinBid(healthy)
+		}
+	}
+
 	// Generate docker-compose scripts
 	gd := generate.GenerationData{
-		ExecutionClient:   combinedClients.Execution,
-		ConsensusClient:   combinedClients.Consensus,
-		ValidatorClient:   combinedClients.Validator,
-		GenerationPath:    generationPath,
-		Network:           network,
-		CheckpointSyncUrl: checkpointSyncUrl,
-		FeeRecipient:      feeRecipient,
-		JWTSecretPath:     jwtPath,
-		Graffiti:          graffiti,
-		FallbackELUrls:    *fallbackEL,
-		ElExtraFlags:      *elExtraFlags,
-		ClExtraFlags:      *clExtraFlags,
-		VlExtraFlags:      *vlExtraFlags,
-		MapAllPorts:       mapAllPorts,
-		Mev:               !noMev && !noValidator,
-		MevImage:          mevImage,
-		LoggingDriver:     configs.GetLoggingDriver(loggingFlag),
+		ExecutionClient:         combinedClients.Execution,
+		ConsensusClient:         combinedClients.Consensus,
+		ValidatorClient:         combinedClients.Validator,
+		GenerationPath:          generationPath,
+		Network:                 network,
+		CheckpointSyncUrl:       checkpointSyncUrl,
+		CheckpointSyncDisableCL: checkpointSyncDisableCL,
+		FeeRecipient:            feeRecipient,
+		JWTSecretPath:           jwtPath,
+		KeystorePath:            keystorePath,
+		Graffiti:                graffiti,
+		FallbackELUrls:          *fallbackEL,
+		ElExtraFlags:            *elExtraFlags,
+		ClExtraFlags:            *clExtraFlags,
+		VlExtraFlags:            *vlExtraFlags,
+		MapAllPorts:             mapAllPorts,
+		Mev:                     useMev,
+		MevImage:                mevImage,
+		MevRelayUrls:            relayURLs,
+		MevBuilderFlag:          builderFlag,
+		MevMinBid:               minBid,
+		NetworkConfig:           networkConfig,
+		LoggingDriver:           configs.GetLoggingDriver(loggingFlag),
 	}
 	results, err := generate.GenerateScripts(gd)
 	if err != nil {
@@ -348,7 +450,9 @@ func init() {
 
 	CliCmd.Flags().StringVarP(&generationPath, "path", "p", configs.DefaultDockerComposeScriptsPath, "docker-compose scripts generation path")
 
-	CliCmd.Flags().StringVar(&checkpointSyncUrl, "checkpoint-sync-url", "", "Initial state endpoint (trusted synced consensus endpoint) for the consensus client to sync from a finalized checkpoint. Provide faster sync process for the consensus client and protect it from long-range attacks affored by Weak Subjetivity")
+	CliCmd.Flags().StringVar(&checkpointSyncUrl, "checkpoint-sync-url", "", "Initial state endpoint (trusted synced consensus endpoint) for the consensus client to sync from a finalized checkpoint. Provide faster sync process for the consensus client and protect it from long-range attacks affored by Weak Subjetivity. Accepts 'auto' to probe and pick the freshest well-known endpoint for the target network, or a comma-separated list of candidate URLs to probe")
+
+	CliCmd.Flags().StringVar(&checkpointSync, "checkpoint-sync", "", "Set to 'disable' to explicitly opt out of checkpoint sync, rendering the consensus client flag that turns it off instead of silently falling back to genesis sync")
 
 	CliCmd.Flags().StringVarP(&network, "network", "n", "mainnet", "Target network. e.g. mainnet, goerli, sepolia, etc.")
 
@@ -358,10 +462,16 @@ func init() {
 
 	CliCmd.Flags().StringVarP(&mevImage, "mev-boost-image", "m", "", "Custom docker image to use for Mev Boost. Example: 'sedge cli --mev-boost-image flashbots/mev-boost:latest-portable'")
 
+	mevRelayFlags = CliCmd.Flags().StringArray("mev-relay", []string{}, "Additional mev-boost relay to use, in 'name=<name>,url=<url>,region=<region>,filter=ethical|regulated|unfiltered,min-bid=<eth>' form. Repeatable. Example: 'sedge cli --mev-relay \"name=flashbots,url=https://boost-relay.flashbots.net,region=us-east,filter=ethical\"'")
+
+	CliCmd.Flags().StringVar(&mevProfile, "mev-profile", "", "Shorthand that expands to a curated relay set for the target network. Possible values: ethical, max-profit, regulated")
+
 	CliCmd.Flags().BoolVar(&noValidator, "no-validator", false, "Exclude the validator from the full node setup. Designed for execution and consensus nodes setup without a validator node. Exclude also the validator from other flags. If set, mev-boost will not be used.")
 
 	CliCmd.Flags().StringVar(&jwtPath, "jwt-secret-path", "", "Path to the JWT secret file")
 
+	CliCmd.Flags().StringVar(&keystorePath, "keystore-path", "", "Path to existing validator keystores to import, mounted into the validator client's container")
+
 	CliCmd.Flags().StringVar(&graffiti, "graffiti", "", "Graffiti to be used by the validator")
 
 	CliCmd.Flags().BoolVarP(&install, "install", "i", false, "Install dependencies if not installed without asking")
@@ -382,8 +492,24 @@ func init() {
 
 	vlExtraFlags = CliCmd.Flags().StringArray("vl-extra-flag", []string{}, "Additional flag to configure the validator client service in the generated docker-compose script. Example: 'sedge cli --vl-extra-flag \"<flag1>=value1\" --vl-extra-flag \"<flag2>=\\\"value2\\\"\"'")
 
+	CliCmd.Flags().StringVar(&dockerNetworkName, "docker-network-name", "", "Name of the docker network to create (or, with --docker-network-external, to reuse) for the generated services. Defaults to the compose project's default network")
+
+	CliCmd.Flags().StringVar(&dockerNetworkDriver, "docker-network-driver", "bridge", "Docker network driver to use when creating the network, e.g. bridge, macvlan, ipvlan")
+
+	CliCmd.Flags().StringVar(&dockerNetworkSubnet, "docker-network-subnet", "", "Subnet (CIDR) for the created docker network, e.g. 172.30.0.0/24")
+
+	CliCmd.Flags().StringVar(&dockerNetworkGateway, "docker-network-gateway", "", "Gateway address for the created docker network, must be inside --docker-network-subnet")
+
+	CliCmd.Flags().BoolVar(&dockerNetworkExternal, "docker-network-external", false, "Use a pre-existing docker network (named by --docker-network-name) instead of creating one")
+
+	exposedPorts = CliCmd.Flags().StringArray("expose-port", []string{}, "Map an individual service port to the host, in 'service:container-port[:host-port][/proto][@cidr]' form. Repeatable. Example: 'sedge cli --expose-port validator:5052:15052/tcp@10.0.0.0/24'")
+
 	CliCmd.Flags().StringVar(&loggingFlag, "logging", "json", fmt.Sprintf("Docker logging driver used by all the services. Set 'none' to use the default docker logging driver. Possible values: %v", configs.ValidLoggingFlags()))
 
+	CliCmd.Flags().StringVar(&configFilePath, "config-file", "", "Path to a JSON/YAML file with any of the flags above except --install, --run and --yes. Precedence is: built-in defaults < config file < environment variables < explicit CLI flags")
+
+	CliCmd.Flags().BoolVar(&printConfig, "print-config", false, "Print the effective configuration, after merging defaults, config file and flags, as YAML and exit without generating or running anything")
+
 	// Initialize monitoring tool
 	initMonitor(func() MonitoringTool {
 		// Initialize Eth2 Monitoring tool
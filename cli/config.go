@@ -0,0 +1,539 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/NethermindEth/sedge/pkg/profile"
+)
+
+// sedgeEnvPrefix is the prefix environment variables use to set a cliConfig
+// field, e.g. SEDGE_NETWORK, SEDGE_NO_MEV_BOOST, SEDGE_RUN_CLIENTS. The
+// suffix is a flag name with dashes turned to underscores and upper-cased.
+const sedgeEnvPrefix = "SEDGE_"
+
+// cliConfig mirrors the CliCmd flags that shape a run, so a run can be
+// described by a JSON/YAML document instead of a long flag list. It has no
+// fields for --install, --run, --yes, --config-file, --print-config or
+// --profile.
+//
+// Fields use pointers/slices so the merge step can tell "not set" apart
+// from "set to the zero value".
+type cliConfig struct {
+	Execution             string   `yaml:"execution,omitempty" json:"execution,omitempty"`
+	ExecutionImage        string   `yaml:"execution-image,omitempty" json:"execution-image,omitempty"`
+	Consensus             string   `yaml:"consensus,omitempty" json:"consensus,omitempty"`
+	ConsensusImage        string   `yaml:"consensus-image,omitempty" json:"consensus-image,omitempty"`
+	Validator             string   `yaml:"validator,omitempty" json:"validator,omitempty"`
+	ValidatorImage        string   `yaml:"validator-image,omitempty" json:"validator-image,omitempty"`
+	Network               string   `yaml:"network,omitempty" json:"network,omitempty"`
+	GenerationPath        string   `yaml:"path,omitempty" json:"path,omitempty"`
+	CheckpointSyncUrl     string   `yaml:"checkpoint-sync-url,omitempty" json:"checkpoint-sync-url,omitempty"`
+	FeeRecipient          string   `yaml:"fee-recipient,omitempty" json:"fee-recipient,omitempty"`
+	JWTSecretPath         string   `yaml:"jwt-secret-path,omitempty" json:"jwt-secret-path,omitempty"`
+	KeystorePath          string   `yaml:"keystore-path,omitempty" json:"keystore-path,omitempty"`
+	Graffiti              string   `yaml:"graffiti,omitempty" json:"graffiti,omitempty"`
+	FallbackExecutionURLs []string `yaml:"fallback-execution-urls,omitempty" json:"fallback-execution-urls,omitempty"`
+	ElExtraFlags          []string `yaml:"el-extra-flag,omitempty" json:"el-extra-flag,omitempty"`
+	ClExtraFlags          []string `yaml:"cl-extra-flag,omitempty" json:"cl-extra-flag,omitempty"`
+	VlExtraFlags          []string `yaml:"vl-extra-flag,omitempty" json:"vl-extra-flag,omitempty"`
+	RunClients            []string `yaml:"run-clients,omitempty" json:"run-clients,omitempty"`
+	MapAllPorts           *bool    `yaml:"map-all,omitempty" json:"map-all,omitempty"`
+	NoMevBoost            *bool    `yaml:"no-mev-boost,omitempty" json:"no-mev-boost,omitempty"`
+	NoValidator           *bool    `yaml:"no-validator,omitempty" json:"no-validator,omitempty"`
+	MevBoostImage         string   `yaml:"mev-boost-image,omitempty" json:"mev-boost-image,omitempty"`
+	MevRelays             []string `yaml:"mev-relay,omitempty" json:"mev-relay,omitempty"`
+	MevProfile            string   `yaml:"mev-profile,omitempty" json:"mev-profile,omitempty"`
+	CheckpointSync        string   `yaml:"checkpoint-sync,omitempty" json:"checkpoint-sync,omitempty"`
+	Logging               string   `yaml:"logging,omitempty" json:"logging,omitempty"`
+	DockerNetworkName     string   `yaml:"docker-network-name,omitempty" json:"docker-network-name,omitempty"`
+	DockerNetworkDriver   string   `yaml:"docker-network-driver,omitempty" json:"docker-network-driver,omitempty"`
+	DockerNetworkSubnet   string   `yaml:"docker-network-subnet,omitempty" json:"docker-network-subnet,omitempty"`
+	DockerNetworkGateway  string   `yaml:"docker-network-gateway,omitempty" json:"docker-network-gateway,omitempty"`
+	DockerNetworkExternal *bool    `yaml:"docker-network-external,omitempty" json:"docker-network-external,omitempty"`
+	ExposedPorts          []string `yaml:"expose-port,omitempty" json:"expose-port,omitempty"`
+}
+
+// flatOptions lists the cliConfig fields that are replaced wholesale during
+// a merge instead of recursively combined, mirroring dockerd's flatOptions
+// allowlist for daemon.json. Every slice field in cliConfig belongs here:
+// without it, a config-file list and a CLI-flag list would be concatenated
+// instead of the flag list overriding the file, which is not what users of
+// a "file < env < flags" precedence chain expect from a list-typed flag.
+var flatOptions = map[string]bool{
+	"fallback-execution-urls": true,
+	"el-extra-flag":           true,
+	"cl-extra-flag":           true,
+	"vl-extra-flag":           true,
+	"run-clients":             true,
+	"mev-relay":               true,
+	"expose-port":             true,
+}
+
+// loadCliConfig reads a JSON or YAML config-file document. Both formats are
+// accepted through the same decoder since cliConfig's yaml tags are valid
+// JSON keys and YAML is a superset of JSON.
+func loadCliConfig(path string) (*cliConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	var c cliConfig
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// mergeCliConfig deep-merges src into dst, only overwriting fields that are
+// non-zero in src. Slice fields named in flatOptions are replaced rather
+// than appended, matching the documented "file < env < flags" precedence:
+// each layer call replaces the prior layer's value for a given field.
+func mergeCliConfig(dst *cliConfig, src *cliConfig) {
+	if src.Execution != "" {
+		dst.Execution = src.Execution
+	}
+	if src.ExecutionImage != "" {
+		dst.ExecutionImage = src.ExecutionImage
+	}
+	if src.Consensus != "" {
+		dst.Consensus = src.Consensus
+	}
+	if src.ConsensusImage != "" {
+		dst.ConsensusImage = src.ConsensusImage
+	}
+	if src.Validator != "" {
+		dst.Validator = src.Validator
+	}
+	if src.ValidatorImage != "" {
+		dst.ValidatorImage = src.ValidatorImage
+	}
+	if src.Network != "" {
+		dst.Network = src.Network
+	}
+	if src.GenerationPath != "" {
+		dst.GenerationPath = src.GenerationPath
+	}
+	if src.CheckpointSyncUrl != "" {
+		dst.CheckpointSyncUrl = src.CheckpointSyncUrl
+	}
+	if src.FeeRecipient != "" {
+		dst.FeeRecipient = src.FeeRecipient
+	}
+	if src.JWTSecretPath != "" {
+		dst.JWTSecretPath = src.JWTSecretPath
+	}
+	if src.KeystorePath != "" {
+		dst.KeystorePath = src.KeystorePath
+	}
+	if src.Graffiti != "" {
+		dst.Graffiti = src.Graffiti
+	}
+	if src.MevBoostImage != "" {
+		dst.MevBoostImage = src.MevBoostImage
+	}
+	if src.MevProfile != "" {
+		dst.MevProfile = src.MevProfile
+	}
+	if src.CheckpointSync != "" {
+		dst.CheckpointSync = src.CheckpointSync
+	}
+	if src.Logging != "" {
+		dst.Logging = src.Logging
+	}
+	if src.DockerNetworkName != "" {
+		dst.DockerNetworkName = src.DockerNetworkName
+	}
+	if src.DockerNetworkDriver != "" {
+		dst.DockerNetworkDriver = src.DockerNetworkDriver
+	}
+	if src.DockerNetworkSubnet != "" {
+		dst.DockerNetworkSubnet = src.DockerNetworkSubnet
+	}
+	if src.DockerNetworkGateway != "" {
+		dst.DockerNetworkGateway = src.DockerNetworkGateway
+	}
+	if src.MapAllPorts != nil {
+		dst.MapAllPorts = src.MapAllPorts
+	}
+	if src.NoMevBoost != nil {
+		dst.NoMevBoost = src.NoMevBoost
+	}
+	if src.NoValidator != nil {
+		dst.NoValidator = src.NoValidator
+	}
+	if src.DockerNetworkExternal != nil {
+		dst.DockerNetworkExternal = src.DockerNetworkExternal
+	}
+	// flatOptions: replace wholesale, never concatenate.
+	if len(src.FallbackExecutionURLs) > 0 {
+		dst.FallbackExecutionURLs = src.FallbackExecutionURLs
+	}
+	if len(src.ElExtraFlags) > 0 {
+		dst.ElExtraFlags = src.ElExtraFlags
+	}
+	if len(src.ClExtraFlags) > 0 {
+		dst.ClExtraFlags = src.ClExtraFlags
+	}
+	if len(src.VlExtraFlags) > 0 {
+		dst.VlExtraFlags = src.VlExtraFlags
+	}
+	if len(src.RunClients) > 0 {
+		dst.RunClients = src.RunClients
+	}
+	if len(src.MevRelays) > 0 {
+		dst.MevRelays = src.MevRelays
+	}
+	if len(src.ExposedPorts) > 0 {
+		dst.ExposedPorts = src.ExposedPorts
+	}
+}
+
+// cliConfigFromFlags snapshots the current flag values into a cliConfig so
+// it can take part in the same merge chain as the config file.
+func cliConfigFromFlags() *cliConfig {
+	return &cliConfig{
+		Execution:             executionName,
+		Consensus:             consensusName,
+		Validator:             validatorName,
+		Network:               network,
+		GenerationPath:        generationPath,
+		CheckpointSyncUrl:     checkpointSyncUrl,
+		FeeRecipient:          feeRecipient,
+		JWTSecretPath:         jwtPath,
+		KeystorePath:          keystorePath,
+		Graffiti:              graffiti,
+		MevBoostImage:         mevImage,
+		MevProfile:            mevProfile,
+		CheckpointSync:        checkpointSync,
+		Logging:               loggingFlag,
+		DockerNetworkName:     dockerNetworkName,
+		DockerNetworkDriver:   dockerNetworkDriver,
+		DockerNetworkSubnet:   dockerNetworkSubnet,
+		DockerNetworkGateway:  dockerNetworkGateway,
+		MapAllPorts:           &mapAllPorts,
+		NoMevBoost:            &noMev,
+		NoValidator:           &noValidator,
+		DockerNetworkExternal: &dockerNetworkExternal,
+		FallbackExecutionURLs: *fallbackEL,
+		ElExtraFlags:          *elExtraFlags,
+		ClExtraFlags:          *clExtraFlags,
+		VlExtraFlags:          *vlExtraFlags,
+		RunClients:            *services,
+		MevRelays:             *mevRelayFlags,
+		ExposedPorts:          *exposedPorts,
+	}
+}
+
+// applyCliConfig writes a merged cliConfig back into the package-level flag
+// variables CliCmd's Run/PreRun read from, so the rest of the command is
+// unaware whether a value came from a default, a config file, or a flag.
+func applyCliConfig(c *cliConfig) {
+	executionName = c.Execution
+	consensusName = c.Consensus
+	validatorName = c.Validator
+	// The *-image keys are folded into the '<client>:<image>' form that
+	// preRunCliCmd splits, unless the client value already carries an image.
+	if c.ExecutionImage != "" && !strings.Contains(c.Execution, ":") {
+		executionName = c.Execution + ":" + c.ExecutionImage
+	}
+	if c.ConsensusImage != "" && !strings.Contains(c.Consensus, ":") {
+		consensusName = c.Consensus + ":" + c.ConsensusImage
+	}
+	if c.ValidatorImage != "" && !strings.Contains(c.Validator, ":") {
+		validatorName = c.Validator + ":" + c.ValidatorImage
+	}
+	network = c.Network
+	generationPath = c.GenerationPath
+	checkpointSyncUrl = c.CheckpointSyncUrl
+	feeRecipient = c.FeeRecipient
+	jwtPath = c.JWTSecretPath
+	keystorePath = c.KeystorePath
+	graffiti = c.Graffiti
+	mevImage = c.MevBoostImage
+	mevProfile = c.MevProfile
+	checkpointSync = c.CheckpointSync
+	loggingFlag = c.Logging
+	dockerNetworkName = c.DockerNetworkName
+	dockerNetworkDriver = c.DockerNetworkDriver
+	dockerNetworkSubnet = c.DockerNetworkSubnet
+	dockerNetworkGateway = c.DockerNetworkGateway
+	if c.MapAllPorts != nil {
+		mapAllPorts = *c.MapAllPorts
+	}
+	if c.NoMevBoost != nil {
+		noMev = *c.NoMevBoost
+	}
+	if c.NoValidator != nil {
+		noValidator = *c.NoValidator
+	}
+	if c.DockerNetworkExternal != nil {
+		dockerNetworkExternal = *c.DockerNetworkExternal
+	}
+	*fallbackEL = c.FallbackExecutionURLs
+	*elExtraFlags = c.ElExtraFlags
+	*clExtraFlags = c.ClExtraFlags
+	*vlExtraFlags = c.VlExtraFlags
+	*services = c.RunClients
+	*mevRelayFlags = c.MevRelays
+	*exposedPorts = c.ExposedPorts
+}
+
+// resolveCliConfig builds the effective configuration by merging, in
+// increasing order of precedence: built-in flag defaults, the config file
+// given by --config-file (if any), the wizard profile given by --profile
+// (if any), environment variables (SEDGE_* prefix,
+// see cliConfigFromEnv), and explicit CLI flags (cmd.Flags().Changed).
+func resolveCliConfig(cmd *cobra.Command) (*cliConfig, error) {
+	merged := cliConfigFromFlags()
+
+	if configFilePath != "" {
+		fileCfg, err := loadCliConfig(configFilePath)
+		if err != nil {
+			return nil, err
+		}
+		mergeCliConfig(merged, fileCfg)
+	}
+
+	if profileName != "" {
+		p, err := profile.Load(profileName)
+		if err != nil {
+			return nil, err
+		}
+		mergeCliConfig(merged, cliConfigFromProfile(p))
+	}
+
+	envCfg, err := cliConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	mergeCliConfig(merged, envCfg)
+
+	// Explicit CLI flags always win, regardless of config file, profile or
+	// environment variable contents.
+	flagCfg := &cliConfig{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		setChangedFlag(flagCfg, f.Name)
+	})
+	mergeCliConfig(merged, flagCfg)
+
+	return merged, nil
+}
+
+// setChangedFlag copies the current value of a single explicitly-set flag
+// into flagCfg, keyed by its pflag name (matching cliConfig's yaml tags).
+func setChangedFlag(flagCfg *cliConfig, name string) {
+	switch name {
+	case "execution":
+		flagCfg.Execution = executionName
+	case "consensus":
+		flagCfg.Consensus = consensusName
+	case "validator":
+		flagCfg.Validator = validatorName
+	case "network":
+		flagCfg.Network = network
+	case "path":
+		flagCfg.GenerationPath = generationPath
+	case "checkpoint-sync-url":
+		flagCfg.CheckpointSyncUrl = checkpointSyncUrl
+	case "fee-recipient":
+		flagCfg.FeeRecipient = feeRecipient
+	case "jwt-secret-path":
+		flagCfg.JWTSecretPath = jwtPath
+	case "keystore-path":
+		flagCfg.KeystorePath = keystorePath
+	case "graffiti":
+		flagCfg.Graffiti = graffiti
+	case "mev-boost-image":
+		flagCfg.MevBoostImage = mevImage
+	case "mev-relay":
+		flagCfg.MevRelays = *mevRelayFlags
+	case "mev-profile":
+		flagCfg.MevProfile = mevProfile
+	case "checkpoint-sync":
+		flagCfg.CheckpointSync = checkpointSync
+	case "logging":
+		flagCfg.Logging = loggingFlag
+	case "docker-network-name":
+		flagCfg.DockerNetworkName = dockerNetworkName
+	case "docker-network-driver":
+		flagCfg.DockerNetworkDriver = dockerNetworkDriver
+	case "docker-network-subnet":
+		flagCfg.DockerNetworkSubnet = dockerNetworkSubnet
+	case "docker-network-gateway":
+		flagCfg.DockerNetworkGateway = dockerNetworkGateway
+	case "docker-network-external":
+		flagCfg.DockerNetworkExternal = &dockerNetworkExternal
+	case "expose-port":
+		flagCfg.ExposedPorts = *exposedPorts
+	case "map-all":
+		flagCfg.MapAllPorts = &mapAllPorts
+	case "no-mev-boost":
+		flagCfg.NoMevBoost = &noMev
+	case "no-validator":
+		flagCfg.NoValidator = &noValidator
+	case "fallback-execution-urls":
+		flagCfg.FallbackExecutionURLs = *fallbackEL
+	case "el-extra-flag":
+		flagCfg.ElExtraFlags = *elExtraFlags
+	case "cl-extra-flag":
+		flagCfg.ClExtraFlags = *clExtraFlags
+	case "vl-extra-flag":
+		flagCfg.VlExtraFlags = *vlExtraFlags
+	case "run-clients":
+		flagCfg.RunClients = *services
+	}
+}
+
+// envName turns a flag name into its SEDGE_* environment variable
+// equivalent, e.g. "fee-recipient" -> "SEDGE_FEE_RECIPIENT".
+func envName(flagName string) string {
+	return sedgeEnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// cliConfigFromEnv reads every flag cliConfig understands from its
+// SEDGE_<FLAG_NAME> environment variable, so "sedge cli" can be configured
+// in environments (CI, systemd units, docker) where passing a long flag
+// list or config file isn't convenient. Unset variables leave the
+// corresponding field at its zero value, which mergeCliConfig treats as
+// "not set".
+func cliConfigFromEnv() (*cliConfig, error) {
+	c := &cliConfig{}
+
+	str := func(flagName string, dst *string) {
+		if v, ok := os.LookupEnv(envName(flagName)); ok {
+			*dst = v
+		}
+	}
+	list := func(flagName string, dst *[]string) {
+		if v, ok := os.LookupEnv(envName(flagName)); ok {
+			*dst = strings.Split(v, ",")
+		}
+	}
+	boolean := func(flagName string, dst **bool) error {
+		v, ok := os.LookupEnv(envName(flagName))
+		if !ok {
+			return nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", envName(flagName), err)
+		}
+		*dst = &b
+		return nil
+	}
+
+	str("execution", &c.Execution)
+	str("consensus", &c.Consensus)
+	str("validator", &c.Validator)
+	str("network", &c.Network)
+	str("path", &c.GenerationPath)
+	str("checkpoint-sync-url", &c.CheckpointSyncUrl)
+	str("fee-recipient", &c.FeeRecipient)
+	str("jwt-secret-path", &c.JWTSecretPath)
+	str("keystore-path", &c.KeystorePath)
+	str("graffiti", &c.Graffiti)
+	str("mev-boost-image", &c.MevBoostImage)
+	str("mev-profile", &c.MevProfile)
+	str("checkpoint-sync", &c.CheckpointSync)
+	str("logging", &c.Logging)
+	str("docker-network-name", &c.DockerNetworkName)
+	str("docker-network-driver", &c.DockerNetworkDriver)
+	str("docker-network-subnet", &c.DockerNetworkSubnet)
+	str("docker-network-gateway", &c.DockerNetworkGateway)
+	list("fallback-execution-urls", &c.FallbackExecutionURLs)
+	list("el-extra-flag", &c.ElExtraFlags)
+	list("cl-extra-flag", &c.ClExtraFlags)
+	list("vl-extra-flag", &c.VlExtraFlags)
+	list("run-clients", &c.RunClients)
+	list("mev-relay", &c.MevRelays)
+	list("expose-port", &c.ExposedPorts)
+	if err := boolean("map-all", &c.MapAllPorts); err != nil {
+		return nil, err
+	}
+	if err := boolean("no-mev-boost", &c.NoMevBoost); err != nil {
+		return nil, err
+	}
+	if err := boolean("no-validator", &c.NoValidator); err != nil {
+		return nil, err
+	}
+	if err := boolean("docker-network-external", &c.DockerNetworkExternal); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// cliConfigFromProfile adapts a saved wizard profile.Profile into the same
+// cliConfig shape used by the config-file and flag layers, so it can be
+// merged through mergeCliConfig like any other layer.
+func cliConfigFromProfile(p *profile.Profile) *cliConfig {
+	mev := p.Mev
+	return &cliConfig{
+		Execution:         p.Execution,
+		Consensus:         p.Consensus,
+		Validator:         p.Validator,
+		Network:           p.Network,
+		FeeRecipient:      p.FeeRecipient,
+		CheckpointSyncUrl: p.CheckpointSyncUrl,
+		KeystorePath:      p.KeystorePath,
+		Graffiti:          p.Graffiti,
+		NoMevBoost:        boolPtr(!mev),
+		ExposedPorts:      exposedPortsFromProfile(p.Ports),
+	}
+}
+
+// exposedPortsFromProfile turns the wizard's service->host-port overrides
+// into --expose-port-compatible "service:port" entries, sorted by service
+// for a deterministic effective config.
+func exposedPortsFromProfile(ports map[string]string) []string {
+	if len(ports) == 0 {
+		return nil
+	}
+	services := make([]string, 0, len(ports))
+	for service := range ports {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	entries := make([]string, 0, len(services))
+	for _, service := range services {
+		entries = append(entries, fmt.Sprintf("%s:%s", service, ports[service]))
+	}
+	return entries
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// printEffectiveConfig renders the merged, post-precedence configuration as
+// YAML so a run can be reproduced exactly, e.g. shared across a fleet of
+// machines running the same sedge cli invocation.
+func printEffectiveConfig(cmd *cobra.Command, c *cliConfig) error {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to render effective config: %w", err)
+	}
+	fmt.Fprint(cmd.OutOrStdout(), string(out))
+	return nil
+}
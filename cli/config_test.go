@@ -0,0 +1,121 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NethermindEth/sedge/pkg/profile"
+)
+
+func TestMergeCliConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  *cliConfig
+		src  *cliConfig
+		want *cliConfig
+	}{
+		{
+			name: "scalar field only overwritten when src is non-zero",
+			dst:  &cliConfig{Network: "mainnet"},
+			src:  &cliConfig{},
+			want: &cliConfig{Network: "mainnet"},
+		},
+		{
+			name: "scalar field overwritten when src is set",
+			dst:  &cliConfig{Network: "mainnet"},
+			src:  &cliConfig{Network: "sepolia"},
+			want: &cliConfig{Network: "sepolia"},
+		},
+		{
+			name: "flatOptions slice is replaced wholesale, not concatenated",
+			dst:  &cliConfig{ElExtraFlags: []string{"--a=1"}},
+			src:  &cliConfig{ElExtraFlags: []string{"--b=2"}},
+			want: &cliConfig{ElExtraFlags: []string{"--b=2"}},
+		},
+		{
+			name: "empty src slice leaves dst slice untouched",
+			dst:  &cliConfig{MevRelays: []string{"name=flashbots,url=https://x"}},
+			src:  &cliConfig{},
+			want: &cliConfig{MevRelays: []string{"name=flashbots,url=https://x"}},
+		},
+		{
+			name: "bool pointer field only overwritten when src sets it",
+			dst:  &cliConfig{NoMevBoost: boolPtr(false)},
+			src:  &cliConfig{},
+			want: &cliConfig{NoMevBoost: boolPtr(false)},
+		},
+		{
+			name: "bool pointer field overwritten when src sets it",
+			dst:  &cliConfig{NoMevBoost: boolPtr(false)},
+			src:  &cliConfig{NoMevBoost: boolPtr(true)},
+			want: &cliConfig{NoMevBoost: boolPtr(true)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mergeCliConfig(tt.dst, tt.src)
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("mergeCliConfig() = %+v, want %+v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestCliConfigFromProfile_CarriesKeystoreAndPorts(t *testing.T) {
+	p := &profile.Profile{
+		Execution:    "geth",
+		KeystorePath: "/home/user/keystores",
+		Ports:        map[string]string{"validator": "15052", "execution": "30303"},
+	}
+
+	got := cliConfigFromProfile(p)
+
+	if got.KeystorePath != p.KeystorePath {
+		t.Errorf("cliConfigFromProfile().KeystorePath = %q, want %q", got.KeystorePath, p.KeystorePath)
+	}
+	want := []string{"execution:30303", "validator:15052"}
+	if !reflect.DeepEqual(got.ExposedPorts, want) {
+		t.Errorf("cliConfigFromProfile().ExposedPorts = %v, want %v", got.ExposedPorts, want)
+	}
+}
+
+func TestExposedPortsFromProfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports map[string]string
+		want  []string
+	}{
+		{name: "nil map", ports: nil, want: nil},
+		{name: "empty map", ports: map[string]string{}, want: nil},
+		{
+			name:  "sorted by service",
+			ports: map[string]string{"validator": "15052", "consensus": "4000", "execution": "30303"},
+			want:  []string{"consensus:4000", "execution:30303", "validator:15052"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exposedPortsFromProfile(tt.ports)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exposedPortsFromProfile(%v) = %v, want %v", tt.ports, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveDevnetMnemonic(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		want string
+	}{
+		{name: "empty flag falls back to the well-known test mnemonic", flag: "", want: devnetDefaultMnemonic},
+		{
+			name: "explicit flag is used as-is",
+			flag: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			want: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveDevnetMnemonic(tt.flag); got != tt.want {
+				t.Errorf("resolveDevnetMnemonic(%q) = %q, want %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollFinalizedEpoch(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantEpoch  uint64
+		wantErr    bool
+	}{
+		{
+			name:       "zero epoch before the chain has finalized anything",
+			statusCode: http.StatusOK,
+			body:       `{"data":{"finalized":{"epoch":"0"}}}`,
+			wantEpoch:  0,
+		},
+		{
+			name:       "non-zero epoch once the devnet finalizes",
+			statusCode: http.StatusOK,
+			body:       `{"data":{"finalized":{"epoch":"12"}}}`,
+			wantEpoch:  12,
+		},
+		{
+			name:       "non-200 status is an error",
+			statusCode: http.StatusServiceUnavailable,
+			body:       "",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				if tt.body != "" {
+					w.Write([]byte(tt.body))
+				}
+			}))
+			defer server.Close()
+
+			got, err := pollFinalizedEpoch(&http.Client{}, server.URL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("pollFinalizedEpoch() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pollFinalizedEpoch() unexpected error: %v", err)
+			}
+			if got != tt.wantEpoch {
+				t.Errorf("pollFinalizedEpoch() = %d, want %d", got, tt.wantEpoch)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NethermindEth/sedge/configs"
+)
+
+// mevRelay is a single entry parsed out of a repeated --mev-relay flag, e.g.
+// "name=flashbots,url=https://boost-relay.flashbots.net,region=us-east,filter=ethical,min-bid=0.05".
+// MinBid only affects generation through mevMinBid, which folds every
+// relay's MinBid into the single global value mev-boost's -min-bid expects.
+type mevRelay struct {
+	Name   string
+	URL    string
+	Region string
+	Filter string
+	MinBid float64
+}
+
+// mevProfiles expands the --mev-profile shorthand into a curated relay set.
+// Actual per-network relay catalogues live in configs.NetworksConfigs; this
+// table only maps a profile name to the filter it selects from there.
+var mevProfiles = map[string]string{
+	"ethical":    "ethical",
+	"max-profit": "unfiltered",
+	"regulated":  "regulated",
+}
+
+// defaultMevProfile is the relay trust tier resolveMevRelays falls back to
+// when neither --mev-relay nor --mev-profile is given, so plain 'sedge cli'
+// keeps enabling mev-boost with sane defaults the way it always has.
+const defaultMevProfile = "ethical"
+
+// parseMevRelayFlag parses one --mev-relay value into a mevRelay.
+func parseMevRelayFlag(raw string) (mevRelay, error) {
+	var r mevRelay
+	for _, field := range strings.Split(raw, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return r, fmt.Errorf(configs.InvalidMevRelayError, raw)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "name":
+			r.Name = value
+		case "url":
+			r.URL = value
+		case "region":
+			r.Region = value
+		case "filter":
+			r.Filter = value
+		case "min-bid":
+			minBid, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return r, fmt.Errorf(configs.InvalidMevRelayError, raw)
+			}
+			r.MinBid = minBid
+		default:
+			return r, fmt.Errorf(configs.InvalidMevRelayError, raw)
+		}
+	}
+	if r.Name == "" || r.URL == "" {
+		return r, fmt.Errorf(configs.InvalidMevRelayError, raw)
+	}
+	return r, nil
+}
+
+// resolveMevRelays turns the --mev-relay/--mev-profile flags into the final
+// relay set for a network: explicit --mev-relay entries are used verbatim,
+// and if --mev-profile is set (or defaulted, see below) its curated relays
+// (from configs.NetworksConfigs[network].MevRelays) are appended, filtered
+// down to the profile's trust tier.
+//
+// If neither flag is given, this falls back to defaultMevProfile instead of
+// returning no relays, so a plain 'sedge cli' run keeps enabling mev-boost
+// by default on networks that support it, matching the tool's long-standing
+// behavior. A network that doesn't support mev-boost is only an error if
+// the caller actually asked for relays; with nothing requested it's simply
+// treated as "mev-boost isn't available here" and an empty set is returned.
+func resolveMevRelays(network, profile string, explicit []mevRelay) ([]mevRelay, error) {
+	netCfg, hasNetCfg := configs.NetworksConfigs[network]
+	requested := profile != "" || len(explicit) > 0
+
+	if requested && (!hasNetCfg || !netCfg.SupportsMev) {
+		return nil, fmt.Errorf(configs.MevNotSupportedError, network)
+	}
+
+	relays := append([]mevRelay{}, explicit...)
+
+	if profile == "" && len(explicit) == 0 {
+		if !hasNetCfg || !netCfg.SupportsMev {
+			return relays, nil
+		}
+		profile = defaultMevProfile
+	}
+
+	if profile != "" {
+		filter, ok := mevProfiles[profile]
+		if !ok {
+			return nil, fmt.Errorf(configs.UnknownMevProfileError, profile)
+		}
+		for _, candidate := range netCfg.MevRelays {
+			if candidate.Filter == filter {
+				relays = append(relays, mevRelay{
+					Name:   candidate.Name,
+					URL:    candidate.URL,
+					Region: candidate.Region,
+					Filter: candidate.Filter,
+				})
+			}
+		}
+	}
+
+	return relays, nil
+}
+
+// healthCheckMevRelays probes each relay's /eth/v1/builder/status endpoint
+// and drops the ones that don't answer within a short timeout, logging a
+// warning for each one removed.
+func healthCheckMevRelays(relays []mevRelay) []mevRelay {
+	client := &http.Client{Timeout: 5 * time.Second}
+	healthy := make([]mevRelay, 0, len(relays))
+	for _, relay := range relays {
+		resp, err := client.Get(strings.TrimRight(relay.URL, "/") + "/eth/v1/builder/status")
+		if err != nil {
+			log.Warnf(configs.MevRelayUnhealthyWarning, relay.Name, relay.URL)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			log.Warnf(configs.MevRelayUnhealthyWarning, relay.Name, relay.URL)
+			continue
+		}
+		healthy = append(healthy, relay)
+	}
+	return healthy
+}
+
+// mevRelayURLs joins the healthy relay set into the comma-separated list
+// mev-boost and the validator client builder flags expect.
+func mevRelayURLs(relays []mevRelay) string {
+	urls := make([]string, len(relays))
+	for i, relay := range relays {
+		urls[i] = relay.URL
+	}
+	return strings.Join(urls, ",")
+}
+
+// mevMinBid returns the floor mev-boost should pass to its own -min-bid
+// flag, which (unlike filter/region) applies globally rather than per
+// relay: it's the highest min-bid any individual --mev-relay asked for, so
+// every relay's requirement is satisfied. Zero if none of the relays set one.
+func mevMinBid(relays []mevRelay) float64 {
+	var minBid float64
+	for _, relay := range relays {
+		if relay.MinBid > minBid {
+			minBid = relay.MinBid
+		}
+	}
+	return minBid
+}
+
+// validatorBuilderFlag returns the CL-specific flag that toggles builder
+// (MEV) registration on the validator client, since each consensus client
+// names and shapes this knob differently.
+func validatorBuilderFlag(consensusClient string) string {
+	switch consensusClient {
+	case "prysm":
+		return "--enable-builder"
+	case "lighthouse":
+		return "--builder"
+	case "teku":
+		return "--validators-builder-registration-default-enabled=true"
+	case "lodestar":
+		return "--builder"
+	case "nimbus":
+		return "--payload-builder"
+	default:
+		return ""
+	}
+}
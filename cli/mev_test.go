@@ -0,0 +1,167 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/sedge/configs"
+)
+
+func TestParseMevRelayFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    mevRelay
+		wantErr bool
+	}{
+		{
+			name: "name and url only",
+			raw:  "name=flashbots,url=https://boost-relay.flashbots.net",
+			want: mevRelay{Name: "flashbots", URL: "https://boost-relay.flashbots.net"},
+		},
+		{
+			name: "every field",
+			raw:  "name=flashbots,url=https://x,region=us-east,filter=ethical,min-bid=0.05",
+			want: mevRelay{Name: "flashbots", URL: "https://x", Region: "us-east", Filter: "ethical", MinBid: 0.05},
+		},
+		{
+			name:    "missing url",
+			raw:     "name=flashbots",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			raw:     "name=flashbots,url=https://x,bogus=1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid min-bid",
+			raw:     "name=flashbots,url=https://x,min-bid=not-a-number",
+			wantErr: true,
+		},
+		{
+			name:    "malformed pair",
+			raw:     "name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMevRelayFlag(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMevRelayFlag(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseMevRelayFlag(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveMevRelays(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  string
+		profile  string
+		explicit []mevRelay
+		wantLen  int
+		wantErr  bool
+	}{
+		{
+			name:    "nothing requested on a mev network falls back to the default profile",
+			network: "mainnet",
+			wantLen: len(mevNetworkRelaysForFilter(t, "mainnet", "ethical")),
+		},
+		{
+			name:    "nothing requested on a non-mev network returns no relays, no error",
+			network: "gnosis",
+			wantLen: 0,
+		},
+		{
+			name:    "profile requested on a non-mev network is an error",
+			network: "gnosis",
+			profile: "ethical",
+			wantErr: true,
+		},
+		{
+			name:     "explicit relay requested on a non-mev network is an error",
+			network:  "gnosis",
+			explicit: []mevRelay{{Name: "custom", URL: "https://x"}},
+			wantErr:  true,
+		},
+		{
+			name:    "unknown profile is an error",
+			network: "mainnet",
+			profile: "bogus",
+			wantErr: true,
+		},
+		{
+			name:     "explicit relay is always included verbatim",
+			network:  "mainnet",
+			explicit: []mevRelay{{Name: "custom", URL: "https://x"}},
+			wantLen:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveMevRelays(tt.network, tt.profile, tt.explicit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveMevRelays() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("resolveMevRelays() = %d relays, want %d", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+// mevNetworkRelaysForFilter counts how many of network's configured relays
+// match filter, so the "falls back to the default profile" case doesn't
+// hard-code a count that would silently go stale if configs.NetworksConfigs
+// changes.
+func mevNetworkRelaysForFilter(t *testing.T, network, filter string) []string {
+	t.Helper()
+	netCfg, ok := configs.NetworksConfigs[network]
+	if !ok {
+		t.Fatalf("no configs.NetworksConfigs entry for %q", network)
+	}
+	var matched []string
+	for _, r := range netCfg.MevRelays {
+		if r.Filter == filter {
+			matched = append(matched, r.Name)
+		}
+	}
+	return matched
+}
+
+func TestMevMinBid(t *testing.T) {
+	got := mevMinBid([]mevRelay{{MinBid: 0.01}, {MinBid: 0.05}, {MinBid: 0.02}})
+	if got != 0.05 {
+		t.Errorf("mevMinBid() = %v, want 0.05", got)
+	}
+	if got := mevMinBid(nil); got != 0 {
+		t.Errorf("mevMinBid(nil) = %v, want 0", got)
+	}
+}
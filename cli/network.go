@@ -0,0 +1,149 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"github.com/NethermindEth/sedge/configs"
+	"github.com/NethermindEth/sedge/internal/pkg/generate"
+)
+
+// exposedPort is one parsed --expose-port entry, e.g.
+// "validator:5052:15052/tcp@10.0.0.0/24".
+type exposedPort struct {
+	Service       string
+	ContainerPort string
+	HostPort      string
+	Proto         string
+	SourceCIDR    string
+}
+
+// parseExposePortFlag parses a single --expose-port value of the form
+// "service:container-port[:host-port][/proto][@cidr]".
+func parseExposePortFlag(raw string) (exposedPort, error) {
+	var p exposedPort
+	p.Proto = "tcp"
+
+	rest := raw
+	if at := strings.Index(rest, "@"); at != -1 {
+		p.SourceCIDR = rest[at+1:]
+		rest = rest[:at]
+		if _, _, err := net.ParseCIDR(p.SourceCIDR); err != nil {
+			return p, fmt.Errorf(configs.InvalidExposePortError, raw)
+		}
+	}
+	if slash := strings.Index(rest, "/"); slash != -1 {
+		p.Proto = rest[slash+1:]
+		rest = rest[:slash]
+	}
+
+	parts := strings.Split(rest, ":")
+	switch len(parts) {
+	case 2:
+		p.Service, p.ContainerPort = parts[0], parts[1]
+		p.HostPort = parts[1]
+	case 3:
+		p.Service, p.ContainerPort, p.HostPort = parts[0], parts[1], parts[2]
+	default:
+		return p, fmt.Errorf(configs.InvalidExposePortError, raw)
+	}
+	if p.Service == "" || p.ContainerPort == "" {
+		return p, fmt.Errorf(configs.InvalidExposePortError, raw)
+	}
+	return p, nil
+}
+
+// validateDockerNetwork checks that the requested subnet/gateway don't
+// collide with any network docker already knows about. External networks
+// are assumed pre-validated by whoever created them, so this only runs when
+// sedge is about to create a new network.
+func validateDockerNetwork(ctx context.Context, subnet, gateway string) error {
+	if subnet == "" {
+		return nil
+	}
+	_, wantNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return fmt.Errorf(configs.InvalidDockerNetworkSubnetError, subnet)
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("failed to connect to the docker daemon: %w", err)
+	}
+	defer cli.Close()
+
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list existing docker networks: %w", err)
+	}
+
+	for _, existing := range networks {
+		for _, cfg := range existing.IPAM.Config {
+			if cfg.Subnet == "" {
+				continue
+			}
+			_, existingNet, err := net.ParseCIDR(cfg.Subnet)
+			if err != nil {
+				continue
+			}
+			if existingNet.Contains(wantNet.IP) || wantNet.Contains(existingNet.IP) {
+				return fmt.Errorf(configs.DockerNetworkCollisionError, subnet, existing.Name, cfg.Subnet)
+			}
+		}
+	}
+
+	if gateway != "" && !wantNet.Contains(net.ParseIP(gateway)) {
+		return fmt.Errorf(configs.DockerNetworkGatewayOutsideSubnetError, gateway, subnet)
+	}
+
+	return nil
+}
+
+// buildNetworkConfig assembles generate.NetworkConfig from the
+// --docker-network-* and --expose-port flags, to be consumed by the
+// compose templates.
+func buildNetworkConfig(name, driver, subnet, gateway string, external bool, rawExposedPorts []string) (generate.NetworkConfig, error) {
+	ports := make([]generate.ExposedPort, 0, len(rawExposedPorts))
+	for _, raw := range rawExposedPorts {
+		p, err := parseExposePortFlag(raw)
+		if err != nil {
+			return generate.NetworkConfig{}, err
+		}
+		ports = append(ports, generate.ExposedPort{
+			Service:       p.Service,
+			ContainerPort: p.ContainerPort,
+			HostPort:      p.HostPort,
+			Proto:         p.Proto,
+			SourceCIDR:    p.SourceCIDR,
+		})
+	}
+
+	return generate.NetworkConfig{
+		Name:     name,
+		Driver:   driver,
+		Subnet:   subnet,
+		Gateway:  gateway,
+		External: external,
+		Ports:    ports,
+	}, nil
+}
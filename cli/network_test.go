@@ -0,0 +1,90 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseExposePortFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    exposedPort
+		wantErr bool
+	}{
+		{
+			name: "container port only, defaults host port and proto",
+			raw:  "validator:5052",
+			want: exposedPort{Service: "validator", ContainerPort: "5052", HostPort: "5052", Proto: "tcp"},
+		},
+		{
+			name: "explicit host port",
+			raw:  "validator:5052:15052",
+			want: exposedPort{Service: "validator", ContainerPort: "5052", HostPort: "15052", Proto: "tcp"},
+		},
+		{
+			name: "explicit proto and source CIDR",
+			raw:  "validator:5052:15052/udp@10.0.0.0/24",
+			want: exposedPort{Service: "validator", ContainerPort: "5052", HostPort: "15052", Proto: "udp", SourceCIDR: "10.0.0.0/24"},
+		},
+		{
+			name:    "missing service",
+			raw:     ":5052",
+			wantErr: true,
+		},
+		{
+			name:    "too many colon-separated parts",
+			raw:     "validator:5052:15052:extra",
+			wantErr: true,
+		},
+		{
+			name:    "invalid source CIDR",
+			raw:     "validator:5052@not-a-cidr",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExposePortFlag(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExposePortFlag(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseExposePortFlag(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDockerNetwork_NoSubnet(t *testing.T) {
+	// No subnet requested: nothing to validate, and no docker daemon call
+	// should even be attempted.
+	if err := validateDockerNetwork(context.Background(), "", ""); err != nil {
+		t.Errorf("validateDockerNetwork(\"\", \"\") error = %v, want nil", err)
+	}
+}
+
+func TestValidateDockerNetwork_InvalidSubnet(t *testing.T) {
+	if err := validateDockerNetwork(context.Background(), "not-a-cidr", ""); err == nil {
+		t.Error("validateDockerNetwork() error = nil, want an error for an invalid --docker-network-subnet")
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/NethermindEth/sedge/internal/ui"
+)
+
+var wizardProfileName string
+
+// WizardCmd runs the interactive client/network/MEV/fee-recipient/
+// checkpoint-sync/keystore/graffiti/port-mapping flow and saves the result
+// as a named profile that a later non-interactive 'sedge cli --profile'
+// run can load.
+var WizardCmd = &cobra.Command{
+	Use:   "wizard",
+	Short: "Interactively build and save a sedge cli profile",
+	Long: `Run a guided, step-by-step flow that asks the same questions 'sedge cli'
+flags answer on the command line, then saves the result to
+~/.sedge/profiles/<name>.yaml. Quitting mid-way checkpoints progress: the
+next 'sedge cli wizard' invocation offers to resume where you left off.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		saved, err := ui.RunWizard(wizardProfileName)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Profile saved. Run it non-interactively with: sedge cli --profile %s\n", saved.Name)
+		return nil
+	},
+}
+
+func init() {
+	WizardCmd.Flags().StringVar(&wizardProfileName, "name", "default", "Name to save the resulting profile under")
+
+	CliCmd.Flags().StringVar(&profileName, "profile", "", "Name of a profile previously saved with 'sedge cli wizard' to load settings from. Follows the same precedence as --config-file: defaults < profile < environment variables < explicit CLI flags")
+
+	CliCmd.AddCommand(WizardCmd)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configs holds the static, non-secret data cli/ builds its
+// behavior from: per-network catalogues (checkpoint endpoints, mev relays),
+// user-facing message strings, and small validation helpers that don't
+// belong to any one flag.
+package configs
+
+// Network names recognized by utils.SupportedNetworks plus NetworkDevnet,
+// the pseudo-network used for 'sedge cli devnet' runs that never touch
+// configs.NetworksConfigs.
+const (
+	NetworkMainnet = "mainnet"
+	NetworkSepolia = "sepolia"
+	NetworkHolesky = "holesky"
+	NetworkGnosis  = "gnosis"
+	NetworkDevnet  = "devnet"
+)
+
+// DefaultDockerComposeScriptsPath is where 'sedge cli' writes the generated
+// .env and docker-compose.yml when --path isn't given.
+const DefaultDockerComposeScriptsPath = "./sedge-data"
+
+// Info messages.
+const (
+	CheckingDependencies = "Checking dependencies: %s"
+	DependenciesPending  = "Waiting on dependencies: %s"
+	DependenciesOK       = "All dependencies are installed"
+	CreatedFile          = "Created %s"
+	HappyStaking         = "Happy staking!"
+	HappyStaking2        = "Happy staking! Run the generated scripts whenever you're ready"
+	ValidatorTips        = "Your validator client will start once the execution and consensus clients are synced"
+)
+
+// Warnings.
+const (
+	CustomImagesWarning               = "Using custom docker images. Sedge cannot guarantee compatibility with custom images"
+	MapAllPortsWarning                = "Mapping all container ports to the host. Only do this on a trusted network"
+	CheckpointUrlUsedWarning          = "Using checkpoint sync endpoint: %s"
+	NoHealthyMevRelaysWarning         = "None of the configured mev-boost relays passed their health check. Disabling mev-boost for this run"
+	MevRelayUnhealthyWarning          = "Relay %s (%s) failed its health check and was dropped"
+	CheckpointEndpointRejectedWarning = "Rejected checkpoint sync candidate %s: stale or unreachable"
+)
+
+// Errors. Each is a fmt.Errorf/fmt.Sprintf format string; the verbs match
+// the arguments its one or two call sites pass.
+const (
+	RunClientsFlagAmbiguousError   = "invalid --run-clients value %v: 'all' and 'none' must be used alone, not combined with other clients"
+	RunClientsError                = "invalid --run-clients value %q: must be a comma-separated subset of %q"
+	NetworkValidationFailedError   = "failed to validate network: %w"
+	UnknownNetworkError            = "unknown network %q"
+	InvalidFeeRecipientError       = "fee recipient is not a valid 20-byte Ethereum address"
+	InvalidCheckpointSyncFlagError = "invalid --checkpoint-sync value %q: only %q is supported"
+	MonitoringToolInitError        = "failed to initialize monitoring tool: %v"
+
+	InvalidMevRelayError   = "invalid --mev-relay value %q"
+	MevNotSupportedError   = "network %q does not support mev-boost"
+	UnknownMevProfileError = "unknown --mev-profile %q"
+
+	NoCheckpointEndpointsError     = "no well-known checkpoint sync endpoints configured for network %q"
+	NoLiveCheckpointEndpointsError = "none of the candidate checkpoint sync endpoints responded"
+
+	InvalidExposePortError                 = "invalid --expose-port value %q"
+	InvalidDockerNetworkSubnetError        = "invalid --docker-network-subnet %q"
+	DockerNetworkCollisionError            = "--docker-network-subnet %s overlaps with existing docker network %q (%s)"
+	DockerNetworkGatewayOutsideSubnetError = "--docker-network-gateway %s is not inside --docker-network-subnet %s"
+)
+
+// OnPremiseExecutionURL and OnPremiseConsensusURL are the local endpoints
+// the monitoring tool checks against when sedge runs clients on the same
+// machine it's invoked from.
+const (
+	OnPremiseExecutionURL = "http://localhost:8545"
+	OnPremiseConsensusURL = "http://localhost:4000"
+)
+
+// GetDependencies returns the external tools 'sedge cli' needs on the host,
+// checked in preRunCliCmd/runCliCmd before any generation happens.
+func GetDependencies() []string {
+	return []string{"docker", "docker-compose"}
+}
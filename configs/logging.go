@@ -0,0 +1,49 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package configs
+
+import "fmt"
+
+// loggingDrivers maps the --logging flag's accepted values to the docker
+// logging driver they select. "none" keeps docker's own default instead of
+// forcing a driver, which is why it isn't just an alias for "json-file".
+var loggingDrivers = map[string]string{
+	"none":     "",
+	"json":     "json-file",
+	"journald": "journald",
+	"syslog":   "syslog",
+}
+
+// ValidLoggingFlags lists the values --logging accepts, for use in its flag
+// usage string and in ValidateLoggingFlag's error.
+func ValidLoggingFlags() []string {
+	return []string{"none", "json", "journald", "syslog"}
+}
+
+// ValidateLoggingFlag rejects a --logging value that isn't one of
+// ValidLoggingFlags.
+func ValidateLoggingFlag(flag string) error {
+	if _, ok := loggingDrivers[flag]; !ok {
+		return fmt.Errorf("invalid --logging value %q, must be one of %v", flag, ValidLoggingFlags())
+	}
+	return nil
+}
+
+// GetLoggingDriver returns the docker logging driver name for a validated
+// --logging value.
+func GetLoggingDriver(flag string) string {
+	return loggingDrivers[flag]
+}
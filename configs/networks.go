@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package configs
+
+// MevRelayConfig is one entry in a network's curated relay catalogue, as
+// consumed by cli.resolveMevRelays when expanding --mev-profile.
+type MevRelayConfig struct {
+	Name   string
+	URL    string
+	Region string
+	// Filter is the trust tier this relay belongs to: "ethical", "regulated"
+	// or "unfiltered". It's matched against mevProfiles in cli/mev.go.
+	Filter string
+}
+
+// NetworkConfig is the static, per-network catalogue cli/ resolves flags
+// against: which features a network supports and where its well-known
+// public endpoints live.
+type NetworkConfig struct {
+	// SupportsMev gates --mev-relay/--mev-profile: requesting mev-boost on a
+	// network where this is false is an error, not a silent no-op.
+	SupportsMev bool
+	// MevRelays is the curated relay catalogue --mev-profile filters by
+	// MevRelayConfig.Filter.
+	MevRelays []MevRelayConfig
+	// CheckpointSyncEndpoints are the community-run checkpoint sync
+	// endpoints --checkpoint-sync-url=auto probes.
+	CheckpointSyncEndpoints []string
+	// RequireJWT marks networks whose execution clients always enforce the
+	// engine API JWT handshake, so sedge must generate a secret even if the
+	// user didn't ask for one explicitly.
+	RequireJWT bool
+}
+
+// NetworksConfigs is the full per-network catalogue. Networks not listed
+// here (beyond NetworkDevnet, which never looks itself up) support neither
+// mev-boost nor checkpoint-sync auto-selection.
+var NetworksConfigs = map[string]NetworkConfig{
+	NetworkMainnet: {
+		SupportsMev: true,
+		MevRelays: []MevRelayConfig{
+			{Name: "flashbots", URL: "https://boost-relay.flashbots.net", Region: "us-east", Filter: "ethical"},
+			{Name: "ultra-sound", URL: "https://relay.ultrasound.money", Region: "eu-west", Filter: "ethical"},
+			{Name: "agnostic", URL: "https://agnostic-relay.net", Region: "eu-west", Filter: "unfiltered"},
+			{Name: "bloxroute-regulated", URL: "https://bloxroute.regulated.blxrbdn.com", Region: "us-east", Filter: "regulated"},
+		},
+		CheckpointSyncEndpoints: []string{
+			"https://beaconstate.info",
+			"https://sync-mainnet.beaconcha.in",
+			"https://mainnet-checkpoint-sync.attestant.io",
+		},
+		RequireJWT: true,
+	},
+	NetworkSepolia: {
+		SupportsMev: true,
+		MevRelays: []MevRelayConfig{
+			{Name: "flashbots", URL: "https://boost-relay-sepolia.flashbots.net", Region: "us-east", Filter: "ethical"},
+		},
+		CheckpointSyncEndpoints: []string{
+			"https://beaconstate-sepolia.chainsafe.io",
+			"https://checkpoint-sync.sepolia.ethpandaops.io",
+		},
+		RequireJWT: true,
+	},
+	NetworkHolesky: {
+		SupportsMev: true,
+		MevRelays: []MevRelayConfig{
+			{Name: "flashbots", URL: "https://boost-relay-holesky.flashbots.net", Region: "us-east", Filter: "ethical"},
+		},
+		CheckpointSyncEndpoints: []string{
+			"https://checkpoint-sync.holesky.ethpandaops.io",
+		},
+		RequireJWT: true,
+	},
+	NetworkGnosis: {
+		SupportsMev: false,
+		CheckpointSyncEndpoints: []string{
+			"https://checkpoint.gnosischain.com",
+		},
+		RequireJWT: true,
+	},
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients describes the execution/consensus/validator clients sedge
+// knows how to run, and which of them are available for a given network.
+package clients
+
+import "fmt"
+
+// Client is a single execution, consensus or validator client selection:
+// which implementation to run and, optionally, a docker image override.
+type Client struct {
+	Name string
+	// Image overrides the default docker image for Name, e.g. from the
+	// '<client>:<image>' syntax accepted by --execution/--consensus/--validator.
+	Image string
+	// Omited marks a client that was excluded from the run, e.g. the
+	// validator when --no-validator is set.
+	Omited bool
+}
+
+// supportedClients lists, per kind ("execution", "consensus", "validator"),
+// the client names sedge can generate a service for.
+var supportedClients = map[string][]string{
+	"execution": {"geth", "nethermind", "besu", "erigon"},
+	"consensus": {"teku", "lighthouse", "prysm", "lodestar", "nimbus"},
+	"validator": {"teku", "lighthouse", "prysm", "lodestar", "nimbus"},
+}
+
+// ClientInfo resolves the clients available for Network.
+type ClientInfo struct {
+	Network string
+}
+
+// Clients returns, for each requested kind, every client name
+// supportedClients lists for it. Networks don't currently restrict which
+// clients are available, so Network only documents intent for future
+// per-network restrictions.
+func (c ClientInfo) Clients(kinds []string) (map[string][]string, []error) {
+	result := make(map[string][]string, len(kinds))
+	var errs []error
+	for _, kind := range kinds {
+		names, ok := supportedClients[kind]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown client kind %q", kind))
+			continue
+		}
+		result[kind] = names
+	}
+	return result, errs
+}
@@ -0,0 +1,546 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generate renders the .env and docker-compose.yml sedge hands to
+// docker compose, from the client/network/mev/network-config selections
+// cli/ resolves out of flags, a config file, and environment variables.
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/NethermindEth/sedge/internal/pkg/clients"
+)
+
+// ExposedPort is one parsed --expose-port entry, rendered into the compose
+// service's ports block and, when SourceCIDR is set, into sedge-fw's
+// generated firewall rules.
+type ExposedPort struct {
+	Service       string
+	ContainerPort string
+	HostPort      string
+	Proto         string
+	SourceCIDR    string
+}
+
+// NetworkConfig describes the docker network the generated services attach
+// to, built by cli.buildNetworkConfig from the --docker-network-* and
+// --expose-port flags.
+type NetworkConfig struct {
+	Name     string
+	Driver   string
+	Subnet   string
+	Gateway  string
+	External bool
+	Ports    []ExposedPort
+}
+
+// Devnet carries the extra genesis parameters 'sedge cli devnet' needs on
+// top of GenerationData, for a chain that's bootstrapped from scratch
+// instead of joining an existing network: these drive the
+// devnet-bootstrapper service composeTemplate adds ahead of the
+// execution/consensus/validator services, which writes genesis.json,
+// deploys the deposit contract, and derives ValidatorCount keystores from
+// Mnemonic before the other services are allowed to start.
+type Devnet struct {
+	ChainID           uint64
+	SecondsPerSlot    uint64
+	SlotsPerEpoch     uint64
+	ValidatorCount    uint64
+	PrefundedAccounts []string
+	Mnemonic          string
+	BootstrapperImage string
+}
+
+// GenerationData is everything needed to render a single docker-compose
+// bundle: the resolved client selection, network/mev/checkpoint-sync
+// choices, and (for devnet runs only) genesis parameters.
+type GenerationData struct {
+	ExecutionClient clients.Client
+	ConsensusClient clients.Client
+	ValidatorClient clients.Client
+	GenerationPath  string
+	Network         string
+
+	CheckpointSyncUrl       string
+	CheckpointSyncDisableCL string
+
+	FeeRecipient  string
+	JWTSecretPath string
+	KeystorePath  string
+	Graffiti      string
+
+	FallbackELUrls []string
+	ElExtraFlags   []string
+	ClExtraFlags   []string
+	VlExtraFlags   []string
+
+	MapAllPorts bool
+
+	Mev            bool
+	MevImage       string
+	MevRelayUrls   string
+	MevBuilderFlag string
+	MevMinBid      float64
+
+	NetworkConfig NetworkConfig
+	LoggingDriver string
+
+	// Devnet is non-nil only for 'sedge cli devnet' runs.
+	Devnet *Devnet
+}
+
+// Results points at the files GenerateScripts wrote, and the host ports the
+// execution/consensus clients were mapped to so callers (e.g. trackSync,
+// waitForFinalizedEpoch) can reach them without re-deriving the mapping.
+type Results struct {
+	EnvFilePath       string
+	DockerComposePath string
+	ELPort            string
+	CLPort            string
+}
+
+// defaultELPort and defaultCLPort are the host ports mapped for the
+// execution JSON-RPC and consensus beacon API, unless MapAllPorts changes
+// the mapping scheme.
+const (
+	defaultELPort = "8545"
+	defaultCLPort = "4000"
+)
+
+// defaultDevnetBootstrapperImage generates genesis.json, deploys the
+// deposit contract, and derives validator keystores for 'sedge cli
+// devnet', before the execution/consensus/validator services are allowed
+// to start.
+const defaultDevnetBootstrapperImage = "ethpandaops/ethereum-genesis-generator:3.1.0"
+
+// devnetDataDir is where devnet-bootstrapper writes genesis.json, the
+// deposit contract deployment, and validator keystores, bind-mounted under
+// GenerationPath so they're visible and reusable on the host instead of
+// trapped in an anonymous Docker-managed volume.
+const devnetDataDir = "devnet-data"
+
+// templateFuncs are the extra functions composeTemplate needs beyond what
+// text/template provides out of the box.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// composeTemplate renders a minimal but complete docker-compose.yml: one
+// service per non-omitted client, an mev-boost service when gd.Mev is set,
+// a sedge-fw sidecar when any --expose-port entry restricts its source
+// CIDR, and a network block driven by gd.NetworkConfig.
+var composeTemplate = template.Must(template.New("docker-compose.yml").Funcs(templateFuncs).Parse(`version: "3.9"
+services:
+{{- if .Devnet }}
+
+  devnet-bootstrapper:
+    image: {{ .DevnetBootstrapperImage }}
+    container_name: sedge-devnet-bootstrapper
+    restart: "no"
+    environment:
+      - CHAIN_ID={{ .Devnet.ChainID }}
+      - SECONDS_PER_SLOT={{ .Devnet.SecondsPerSlot }}
+      - SLOTS_PER_EPOCH={{ .Devnet.SlotsPerEpoch }}
+      - VALIDATOR_COUNT={{ .Devnet.ValidatorCount }}
+      - MNEMONIC={{ .Devnet.Mnemonic }}
+{{- if .Devnet.PrefundedAccounts }}
+      - PREFUNDED_ACCOUNTS={{ join .Devnet.PrefundedAccounts "," }}
+{{- end }}
+    volumes:
+      - {{ .DevnetDataPath }}:/data
+{{- end }}
+
+  execution:
+    image: {{ .ExecutionImage }}
+    container_name: sedge-execution-client
+    restart: unless-stopped
+    ports:
+      - {{ .ELPort }}:{{ .ELPort }}
+{{- range .PortsFor "execution" }}
+      - {{ .HostPort }}:{{ .ContainerPort }}/{{ .Proto }}
+{{- end }}
+{{- if .Devnet }}
+    depends_on:
+      devnet-bootstrapper:
+        condition: service_completed_successfully
+    volumes:
+      - {{ .DevnetDataPath }}:/genesis:ro
+{{- if .JWTSecretPath }}
+      - {{ .JWTSecretPath }}:/jwt.hex:ro
+{{- end }}
+{{- end }}
+{{- if .NetworkConfig.Name }}
+    networks:
+      - {{ .NetworkConfig.Name }}
+{{- end }}
+
+  consensus:
+    image: {{ .ConsensusImage }}
+    container_name: sedge-consensus-client
+    restart: unless-stopped
+    ports:
+      - {{ .CLPort }}:{{ .CLPort }}
+{{- range .PortsFor "consensus" }}
+      - {{ .HostPort }}:{{ .ContainerPort }}/{{ .Proto }}
+{{- end }}
+{{- if .CheckpointSyncUrl }}
+    environment:
+      - CHECKPOINT_SYNC_URL={{ .CheckpointSyncUrl }}
+{{- end }}
+{{- if .CheckpointSyncDisableCL }}
+    command: ["{{ .CheckpointSyncDisableCL }}"]
+{{- end }}
+{{- if .Devnet }}
+    depends_on:
+      devnet-bootstrapper:
+        condition: service_completed_successfully
+    volumes:
+      - {{ .DevnetDataPath }}:/genesis:ro
+{{- if .JWTSecretPath }}
+      - {{ .JWTSecretPath }}:/jwt.hex:ro
+{{- end }}
+{{- end }}
+{{- if .NetworkConfig.Name }}
+    networks:
+      - {{ .NetworkConfig.Name }}
+{{- end }}
+
+{{- if not .ValidatorOmited }}
+
+  validator:
+    image: {{ .ValidatorImage }}
+    container_name: sedge-validator-client
+    restart: unless-stopped
+{{- if .PortsFor "validator" }}
+    ports:
+{{- range .PortsFor "validator" }}
+      - {{ .HostPort }}:{{ .ContainerPort }}/{{ .Proto }}
+{{- end }}
+{{- end }}
+{{- if .Graffiti }}
+    environment:
+      - GRAFFITI={{ .Graffiti }}
+{{- end }}
+{{- if and .Mev .MevBuilderFlag }}
+    command: ["{{ .MevBuilderFlag }}"]
+{{- end }}
+{{- if .Devnet }}
+    depends_on:
+      devnet-bootstrapper:
+        condition: service_completed_successfully
+    volumes:
+      - {{ .DevnetDataPath }}:/genesis:ro
+{{- end }}
+{{- if .NetworkConfig.Name }}
+    networks:
+      - {{ .NetworkConfig.Name }}
+{{- end }}
+{{- end }}
+
+{{- if .Mev }}
+
+  mev-boost:
+    image: {{ .MevImage }}
+    container_name: sedge-mev-boost
+    restart: unless-stopped
+{{- if .PortsFor "mev-boost" }}
+    ports:
+{{- range .PortsFor "mev-boost" }}
+      - {{ .HostPort }}:{{ .ContainerPort }}/{{ .Proto }}
+{{- end }}
+{{- end }}
+    command:
+      - -relays={{ .MevRelayUrls }}
+{{- if gt .MevMinBid 0.0 }}
+      - -min-bid={{ .MevMinBid }}
+{{- end }}
+{{- if .NetworkConfig.Name }}
+    networks:
+      - {{ .NetworkConfig.Name }}
+{{- end }}
+{{- end }}
+
+{{- if .HasFirewallRules }}
+
+  sedge-fw:
+    image: alpine:3.19
+    container_name: sedge-fw
+    restart: unless-stopped
+    network_mode: host
+    cap_add:
+      - NET_ADMIN
+    entrypoint: ["sh", "-c"]
+    command:
+      - |
+        apk add --no-cache iptables >/dev/null
+{{- range .FirewallRules }}
+{{- $rule := . }}
+{{- range .SourceCIDRs }}
+        iptables -A INPUT -p {{ $rule.Proto }} --dport {{ $rule.HostPort }} -s {{ . }} -j ACCEPT
+{{- end }}
+        iptables -A INPUT -p {{ $rule.Proto }} --dport {{ $rule.HostPort }} -j DROP
+{{- end }}
+        tail -f /dev/null
+{{- end }}
+
+{{- if .NetworkConfig.Name }}
+
+networks:
+  {{ .NetworkConfig.Name }}:
+{{- if .NetworkConfig.External }}
+    external: true
+{{- else }}
+    driver: {{ .NetworkConfig.Driver }}
+    ipam:
+      config:
+        - subnet: {{ .NetworkConfig.Subnet }}
+{{- if .NetworkConfig.Gateway }}
+          gateway: {{ .NetworkConfig.Gateway }}
+{{- end }}
+{{- end }}
+{{- end }}
+`))
+
+// envTemplate renders the .env sidecar file (see results.EnvFilePath) that
+// lists the resolved images, ports and mev/devnet settings. composeTemplate
+// renders the same values inline, so docker-compose.yml does not read them
+// from .env through variable substitution.
+var envTemplate = template.Must(template.New(".env").Parse(`EXECUTION_CLIENT={{ .ExecutionClient.Name }}
+EXECUTION_IMAGE={{ .ExecutionImage }}
+CONSENSUS_CLIENT={{ .ConsensusClient.Name }}
+CONSENSUS_IMAGE={{ .ConsensusImage }}
+VALIDATOR_IMAGE={{ .ValidatorImage }}
+NETWORK={{ .Network }}
+FEE_RECIPIENT={{ .FeeRecipient }}
+JWT_SECRET_PATH={{ .JWTSecretPath }}
+{{- if .KeystorePath }}
+KEYSTORE_PATH={{ .KeystorePath }}
+{{- end }}
+LOGGING_DRIVER={{ .LoggingDriver }}
+EL_PORT={{ .ELPort }}
+CL_PORT={{ .CLPort }}
+{{- if .Mev }}
+MEV_BOOST_IMAGE={{ .MevImage }}
+MEV_RELAY_URLS={{ .MevRelayUrls }}
+MEV_BUILDER_FLAG={{ .MevBuilderFlag }}
+{{- if gt .MevMinBid 0.0 }}
+MEV_MIN_BID={{ .MevMinBid }}
+{{- end }}
+{{- end }}
+{{- if .Devnet }}
+DEVNET_CHAIN_ID={{ .Devnet.ChainID }}
+DEVNET_SECONDS_PER_SLOT={{ .Devnet.SecondsPerSlot }}
+DEVNET_SLOTS_PER_EPOCH={{ .Devnet.SlotsPerEpoch }}
+DEVNET_VALIDATOR_COUNT={{ .Devnet.ValidatorCount }}
+DEVNET_BOOTSTRAPPER_IMAGE={{ .DevnetBootstrapperImage }}
+{{- end }}
+`))
+
+// templateView adapts GenerationData into the flat shape the templates
+// above expect, filling in defaults (images, ports) that GenerationData
+// itself leaves for generate to decide.
+type templateView struct {
+	GenerationData
+	ExecutionImage          string
+	ConsensusImage          string
+	ValidatorImage          string
+	ValidatorOmited         bool
+	ELPort                  string
+	CLPort                  string
+	DevnetBootstrapperImage string
+	DevnetDataPath          string
+}
+
+func newTemplateView(gd GenerationData) templateView {
+	elPort, clPort := defaultELPort, defaultCLPort
+	if gd.MapAllPorts {
+		elPort, clPort = "0", "0"
+	}
+	view := templateView{
+		GenerationData:  gd,
+		ExecutionImage:  imageOrDefault(gd.ExecutionClient),
+		ConsensusImage:  imageOrDefault(gd.ConsensusClient),
+		ValidatorImage:  imageOrDefault(gd.ValidatorClient),
+		ValidatorOmited: gd.ValidatorClient.Omited,
+		ELPort:          elPort,
+		CLPort:          clPort,
+	}
+	if gd.Devnet != nil {
+		view.DevnetBootstrapperImage = gd.Devnet.BootstrapperImage
+		if view.DevnetBootstrapperImage == "" {
+			view.DevnetBootstrapperImage = defaultDevnetBootstrapperImage
+		}
+		view.DevnetDataPath = filepath.Join(gd.GenerationPath, devnetDataDir)
+	}
+	return view
+}
+
+// imageOrDefault returns the client's custom image if one was given,
+// otherwise its own name, mirroring the 'docker pull <name>' convention the
+// rest of sedge assumes for clients without an override.
+func imageOrDefault(c clients.Client) string {
+	if c.Image != "" {
+		return c.Image
+	}
+	return c.Name
+}
+
+// PortsFor returns the NetworkConfig.Ports entries targeting the given
+// compose service, in the order --expose-port was given.
+func (v templateView) PortsFor(service string) []ExposedPort {
+	var ports []ExposedPort
+	for _, p := range v.NetworkConfig.Ports {
+		if p.Service == service {
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+// HasFirewallRules reports whether any --expose-port entry restricted its
+// source CIDR, meaning the sedge-fw sidecar needs to be generated to
+// enforce it: docker's own port publishing has no concept of "only accept
+// from this CIDR", so this is the only place that restriction can be
+// applied.
+func (v templateView) HasFirewallRules() bool {
+	for _, p := range v.NetworkConfig.Ports {
+		if p.SourceCIDR != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// firewallRule is every SourceCIDR --expose-port allowed in for a single
+// proto/host-port pair, so sedge-fw can render all of a port's ACCEPT rules
+// before its one trailing DROP.
+type firewallRule struct {
+	Proto       string
+	HostPort    string
+	SourceCIDRs []string
+}
+
+// FirewallRules groups NetworkConfig.Ports entries that restrict a source
+// CIDR by proto/host port. Grouping matters: iptables evaluates rules
+// top-down, so two --expose-port entries for the same port with different
+// CIDRs must render as ACCEPT, ACCEPT, DROP rather than ACCEPT, DROP,
+// ACCEPT — the latter's first DROP would reject the second CIDR before its
+// ACCEPT rule is ever reached.
+func (v templateView) FirewallRules() []firewallRule {
+	var rules []firewallRule
+	index := make(map[string]int)
+	for _, p := range v.NetworkConfig.Ports {
+		if p.SourceCIDR == "" {
+			continue
+		}
+		key := p.Proto + "/" + p.HostPort
+		if i, ok := index[key]; ok {
+			rules[i].SourceCIDRs = append(rules[i].SourceCIDRs, p.SourceCIDR)
+			continue
+		}
+		index[key] = len(rules)
+		rules = append(rules, firewallRule{Proto: p.Proto, HostPort: p.HostPort, SourceCIDRs: []string{p.SourceCIDR}})
+	}
+	return rules
+}
+
+// GenerateScripts renders gd's .env and docker-compose.yml under
+// gd.GenerationPath, creating the directory if necessary.
+func GenerateScripts(gd GenerationData) (Results, error) {
+	if gd.GenerationPath == "" {
+		return Results{}, fmt.Errorf("generation path must not be empty")
+	}
+	if err := os.MkdirAll(gd.GenerationPath, 0o755); err != nil {
+		return Results{}, fmt.Errorf("failed to create generation path %s: %w", gd.GenerationPath, err)
+	}
+
+	view := newTemplateView(gd)
+
+	if gd.Devnet != nil {
+		if err := os.MkdirAll(view.DevnetDataPath, 0o755); err != nil {
+			return Results{}, fmt.Errorf("failed to create devnet data path %s: %w", view.DevnetDataPath, err)
+		}
+	}
+
+	var composeBuf bytes.Buffer
+	if err := composeTemplate.Execute(&composeBuf, view); err != nil {
+		return Results{}, fmt.Errorf("failed to render docker-compose.yml: %w", err)
+	}
+	var envBuf bytes.Buffer
+	if err := envTemplate.Execute(&envBuf, view); err != nil {
+		return Results{}, fmt.Errorf("failed to render .env: %w", err)
+	}
+
+	envPath := filepath.Join(gd.GenerationPath, ".env")
+	composePath := filepath.Join(gd.GenerationPath, "docker-compose.yml")
+	if err := os.WriteFile(envPath, envBuf.Bytes(), 0o644); err != nil {
+		return Results{}, fmt.Errorf("failed to write %s: %w", envPath, err)
+	}
+	if err := os.WriteFile(composePath, composeBuf.Bytes(), 0o644); err != nil {
+		return Results{}, fmt.Errorf("failed to write %s: %w", composePath, err)
+	}
+
+	return Results{
+		EnvFilePath:       envPath,
+		DockerComposePath: composePath,
+		ELPort:            view.ELPort,
+		CLPort:            view.CLPort,
+	}, nil
+}
+
+// CleanGenerated tidies the files GenerateScripts just wrote: the
+// conditional blocks in composeTemplate/envTemplate leave behind runs of
+// blank lines wherever an optional section (mev-boost, devnet, a custom
+// network) was skipped, and this collapses them down to a single blank line
+// before the files are shown to the user.
+func CleanGenerated(results Results) error {
+	for _, path := range []string{results.EnvFilePath, results.DockerComposePath} {
+		if path == "" {
+			continue
+		}
+		if err := collapseBlankLines(path); err != nil {
+			return fmt.Errorf("failed to clean up %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// collapseBlankLines rewrites path with runs of 2+ consecutive blank lines
+// reduced to one.
+func collapseBlankLines(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	cleaned := make([]string, 0, len(lines))
+	prevBlank := false
+	for _, line := range lines {
+		blank := strings.TrimSpace(line) == ""
+		if blank && prevBlank {
+			continue
+		}
+		cleaned = append(cleaned, line)
+		prevBlank = blank
+	}
+	return os.WriteFile(path, []byte(strings.Join(cleaned, "\n")), 0o644)
+}
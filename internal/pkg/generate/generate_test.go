@@ -0,0 +1,229 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/sedge/internal/pkg/clients"
+)
+
+func TestGenerateScripts_ExposedPortsAndFirewall(t *testing.T) {
+	gd := GenerationData{
+		GenerationPath: t.TempDir(),
+		NetworkConfig: NetworkConfig{
+			Name: "sedge-net",
+			Ports: []ExposedPort{
+				{Service: "validator", ContainerPort: "5052", HostPort: "15052", Proto: "tcp", SourceCIDR: "10.0.0.0/24"},
+				{Service: "execution", ContainerPort: "30303", HostPort: "30303", Proto: "udp"},
+			},
+		},
+	}
+
+	results, err := GenerateScripts(gd)
+	if err != nil {
+		t.Fatalf("GenerateScripts() error = %v", err)
+	}
+
+	composeBytes, err := os.ReadFile(results.DockerComposePath)
+	if err != nil {
+		t.Fatalf("reading %s: %v", results.DockerComposePath, err)
+	}
+	compose := string(composeBytes)
+
+	for _, want := range []string{
+		"- 30303:30303/udp",
+		"- 15052:5052/tcp",
+		"sedge-fw",
+		"iptables -A INPUT -p tcp --dport 15052 -s 10.0.0.0/24 -j ACCEPT",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml missing %q, got:\n%s", want, compose)
+		}
+	}
+}
+
+// TestGenerateScripts_FirewallAcceptsAllCIDRsBeforeDrop guards against
+// iptables's top-down rule evaluation: two --expose-port entries for the
+// same proto/host-port with different source CIDRs must render as
+// ACCEPT, ACCEPT, DROP, since an interleaved ACCEPT, DROP, ACCEPT would let
+// the first DROP reject the second CIDR before its own ACCEPT rule is ever
+// reached.
+func TestGenerateScripts_FirewallAcceptsAllCIDRsBeforeDrop(t *testing.T) {
+	gd := GenerationData{
+		GenerationPath: t.TempDir(),
+		NetworkConfig: NetworkConfig{
+			Ports: []ExposedPort{
+				{Service: "validator", ContainerPort: "5052", HostPort: "15052", Proto: "tcp", SourceCIDR: "10.0.0.0/24"},
+				{Service: "validator", ContainerPort: "5052", HostPort: "15052", Proto: "tcp", SourceCIDR: "10.1.0.0/24"},
+			},
+		},
+	}
+
+	results, err := GenerateScripts(gd)
+	if err != nil {
+		t.Fatalf("GenerateScripts() error = %v", err)
+	}
+
+	composeBytes, err := os.ReadFile(results.DockerComposePath)
+	if err != nil {
+		t.Fatalf("reading %s: %v", results.DockerComposePath, err)
+	}
+	compose := string(composeBytes)
+
+	accept1 := strings.Index(compose, "iptables -A INPUT -p tcp --dport 15052 -s 10.0.0.0/24 -j ACCEPT")
+	accept2 := strings.Index(compose, "iptables -A INPUT -p tcp --dport 15052 -s 10.1.0.0/24 -j ACCEPT")
+	drop := strings.Index(compose, "iptables -A INPUT -p tcp --dport 15052 -j DROP")
+	if accept1 == -1 || accept2 == -1 || drop == -1 {
+		t.Fatalf("docker-compose.yml missing expected iptables rules, got:\n%s", compose)
+	}
+	if !(accept1 < drop && accept2 < drop) {
+		t.Errorf("both ACCEPT rules must precede the DROP rule, got accept1=%d accept2=%d drop=%d:\n%s", accept1, accept2, drop, compose)
+	}
+}
+
+func TestGenerateScripts_NoExposedPortsSkipsFirewall(t *testing.T) {
+	gd := GenerationData{GenerationPath: t.TempDir()}
+
+	results, err := GenerateScripts(gd)
+	if err != nil {
+		t.Fatalf("GenerateScripts() error = %v", err)
+	}
+
+	composeBytes, err := os.ReadFile(results.DockerComposePath)
+	if err != nil {
+		t.Fatalf("reading %s: %v", results.DockerComposePath, err)
+	}
+	compose := string(composeBytes)
+
+	if strings.Contains(compose, "sedge-fw") {
+		t.Errorf("docker-compose.yml should not contain a sedge-fw sidecar when no --expose-port sets a source CIDR, got:\n%s", compose)
+	}
+}
+
+func TestPortsFor(t *testing.T) {
+	view := templateView{
+		GenerationData: GenerationData{
+			NetworkConfig: NetworkConfig{
+				Ports: []ExposedPort{
+					{Service: "validator", ContainerPort: "5052"},
+					{Service: "execution", ContainerPort: "30303"},
+				},
+			},
+		},
+	}
+
+	if got := view.PortsFor("validator"); len(got) != 1 || got[0].ContainerPort != "5052" {
+		t.Errorf("PortsFor(%q) = %+v, want one port with container port 5052", "validator", got)
+	}
+	if got := view.PortsFor("consensus"); len(got) != 0 {
+		t.Errorf("PortsFor(%q) = %+v, want none", "consensus", got)
+	}
+}
+
+func TestGenerateScripts_Devnet(t *testing.T) {
+	path := t.TempDir()
+	gd := GenerationData{
+		ExecutionClient: clients.Client{Name: "geth"},
+		ConsensusClient: clients.Client{Name: "teku"},
+		GenerationPath:  path,
+		Network:         "devnet",
+		JWTSecretPath:   filepath.Join(path, "jwt.hex"),
+		Devnet: &Devnet{
+			ChainID:        1337,
+			SecondsPerSlot: 12,
+			SlotsPerEpoch:  32,
+			ValidatorCount: 64,
+			Mnemonic:       "test test test test test test test test test test test junk",
+		},
+	}
+
+	results, err := GenerateScripts(gd)
+	if err != nil {
+		t.Fatalf("GenerateScripts() error = %v", err)
+	}
+
+	devnetDataPath := filepath.Join(path, devnetDataDir)
+	if info, err := os.Stat(devnetDataPath); err != nil || !info.IsDir() {
+		t.Fatalf("GenerateScripts() did not create devnet data dir %s: %v", devnetDataPath, err)
+	}
+
+	composeBytes, err := os.ReadFile(results.DockerComposePath)
+	if err != nil {
+		t.Fatalf("reading %s: %v", results.DockerComposePath, err)
+	}
+	compose := string(composeBytes)
+
+	for _, want := range []string{
+		"devnet-bootstrapper",
+		devnetDataPath + ":/data",
+		devnetDataPath + ":/genesis:ro",
+		filepath.Join(path, "jwt.hex") + ":/jwt.hex:ro",
+		"condition: service_completed_successfully",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml missing %q, got:\n%s", want, compose)
+		}
+	}
+}
+
+func TestFirewallRules(t *testing.T) {
+	view := templateView{
+		GenerationData: GenerationData{
+			NetworkConfig: NetworkConfig{
+				Ports: []ExposedPort{
+					{Service: "validator", HostPort: "15052", Proto: "tcp", SourceCIDR: "10.0.0.0/24"},
+					{Service: "validator", HostPort: "15052", Proto: "tcp", SourceCIDR: "10.1.0.0/24"},
+					{Service: "execution", HostPort: "30303", Proto: "udp"},
+				},
+			},
+		},
+	}
+
+	got := view.FirewallRules()
+	if len(got) != 1 {
+		t.Fatalf("FirewallRules() = %+v, want one rule", got)
+	}
+	want := []string{"10.0.0.0/24", "10.1.0.0/24"}
+	if !reflect.DeepEqual(got[0].SourceCIDRs, want) {
+		t.Errorf("FirewallRules()[0].SourceCIDRs = %v, want %v", got[0].SourceCIDRs, want)
+	}
+}
+
+func TestHasFirewallRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		ports []ExposedPort
+		want  bool
+	}{
+		{name: "no ports", want: false},
+		{name: "port without source CIDR", ports: []ExposedPort{{Service: "execution", ContainerPort: "30303"}}, want: false},
+		{name: "port with source CIDR", ports: []ExposedPort{{Service: "validator", ContainerPort: "5052", SourceCIDR: "10.0.0.0/24"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := templateView{GenerationData: GenerationData{NetworkConfig: NetworkConfig{Ports: tt.ports}}}
+			if got := view.HasFirewallRules(); got != tt.want {
+				t.Errorf("HasFirewallRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/sedge/pkg/profile"
+	"github.com/NethermindEth/sedge/pkg/wizard"
+)
+
+// RunWizard drives the pkg/wizard bubbletea program to completion, offering
+// to resume a previously checkpointed session if one exists, and saves the
+// resulting profile under profileName.
+func RunWizard(profileName string) (*profile.Profile, error) {
+	resume, err := wizard.LoadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if resume != nil {
+		fmt.Print("Found a previous wizard session. Resume it? [Y/n]: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a == "n" || a == "no" {
+			resume = nil
+		} else {
+			fmt.Println("Resuming a previous wizard session...")
+		}
+	}
+
+	program := wizard.NewProgram(resume)
+	final, err := program.Run()
+	if err != nil {
+		return nil, fmt.Errorf("wizard failed: %w", err)
+	}
+
+	answers, ok := wizard.Result(final)
+	if !ok {
+		return nil, errors.New("wizard exited before completing all steps")
+	}
+
+	answers.Name = profileName
+	if err := profile.Save(answers); err != nil {
+		return nil, err
+	}
+	if err := wizard.ClearCheckpoint(); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
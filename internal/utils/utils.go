@@ -0,0 +1,93 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package utils holds small, dependency-free helpers shared across cli/ and
+// pkg/wizard: slice predicates, host dependency checks, and the handful of
+// validations (network name, fee-recipient address) that don't belong to
+// any one flag.
+package utils
+
+import (
+	"os/exec"
+	"regexp"
+	"sort"
+
+	"github.com/NethermindEth/sedge/configs"
+)
+
+// Contains reports whether item is present in slice.
+func Contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsOnly reports whether every element of slice is also in allowed.
+// An empty slice trivially contains only allowed values.
+func ContainsOnly(slice []string, allowed []string) bool {
+	for _, s := range slice {
+		if !Contains(allowed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the elements of slice for which keep returns true.
+func Filter(slice []string, keep func(string) bool) []string {
+	out := make([]string, 0, len(slice))
+	for _, s := range slice {
+		if keep(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// addressPattern matches a 20-byte Ethereum address: "0x" followed by 40
+// hex digits. It doesn't check the EIP-55 checksum, matching how
+// --fee-recipient has always been validated: case is accepted either way.
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// IsAddress reports whether s looks like a 20-byte Ethereum address.
+func IsAddress(s string) bool {
+	return addressPattern.MatchString(s)
+}
+
+// SupportedNetworks returns the networks sedge has a configs.NetworksConfigs
+// entry for, i.e. every network --network can be set to.
+func SupportedNetworks() ([]string, error) {
+	networks := make([]string, 0, len(configs.NetworksConfigs))
+	for name := range configs.NetworksConfigs {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	return networks, nil
+}
+
+// CheckDependencies returns the subset of deps that aren't on $PATH.
+func CheckDependencies(deps []string) []string {
+	var pending []string
+	for _, dep := range deps {
+		if _, err := exec.LookPath(dep); err != nil {
+			pending = append(pending, dep)
+		}
+	}
+	return pending
+}
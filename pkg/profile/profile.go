@@ -0,0 +1,121 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package profile loads, saves, and lists the YAML profiles produced by
+// `sedge cli wizard`, so a completed wizard run can be replayed
+// non-interactively with `sedge cli --profile <name>`. It has no diff or
+// merge function of its own: profiles are plain YAML files, and merging one
+// into a run is done by the cli package.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir returns the directory sedge stores profiles in, creating it if
+// necessary: ~/.sedge/profiles.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".sedge", "profiles")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create profiles directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// Profile is the set of answers collected by the wizard, serialized as-is
+// to ~/.sedge/profiles/<name>.yaml.
+type Profile struct {
+	Name              string            `yaml:"name"`
+	Execution         string            `yaml:"execution"`
+	Consensus         string            `yaml:"consensus"`
+	Validator         string            `yaml:"validator"`
+	Network           string            `yaml:"network"`
+	Mev               bool              `yaml:"mev"`
+	FeeRecipient      string            `yaml:"fee-recipient"`
+	CheckpointSyncUrl string            `yaml:"checkpoint-sync-url"`
+	KeystorePath      string            `yaml:"keystore-path"`
+	Graffiti          string            `yaml:"graffiti"`
+	Ports             map[string]string `yaml:"ports,omitempty"`
+}
+
+// Path returns the on-disk path for a named profile.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".yaml"), nil
+}
+
+// Save writes p to ~/.sedge/profiles/<p.Name>.yaml.
+func Save(p *Profile) error {
+	path, err := Path(p.Name)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile %s: %w", p.Name, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write profile %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads a named profile back from ~/.sedge/profiles.
+func Load(name string) (*Profile, error) {
+	path, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %s: %w", path, err)
+	}
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// List returns the names of every saved profile.
+func List() ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles directory %s: %w", dir, err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".yaml" {
+			names = append(names, e.Name()[:len(e.Name())-len(".yaml")])
+		}
+	}
+	return names, nil
+}
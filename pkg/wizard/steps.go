@@ -0,0 +1,395 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package wizard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/NethermindEth/sedge/configs"
+	"github.com/NethermindEth/sedge/internal/utils"
+)
+
+// stepModelFor returns the bubbletea sub-model for the given step, seeded
+// with whatever has already been answered in state.Profile.
+func stepModelFor(state State) tea.Model {
+	switch state.Step {
+	case StepClientSelection:
+		return newClientSelectionStep(state)
+	case StepNetwork:
+		return newNetworkStep(state)
+	case StepMev:
+		return newMevStep(state)
+	case StepFeeRecipient:
+		return newFeeRecipientStep(state)
+	case StepCheckpointSync:
+		return newCheckpointSyncStep(state)
+	case StepKeystoreImport:
+		return newKeystoreImportStep(state)
+	case StepGraffiti:
+		return newGraffitiStep(state)
+	case StepPortMapping:
+		return newPortMappingStep(state)
+	default:
+		return doneStep{state: state}
+	}
+}
+
+// doneStep is a no-op terminal model; it's only reachable if stepModelFor
+// is asked for a step past StepPortMapping, which shouldn't normally happen
+// since the wizard program quits on StepDone.
+type doneStep struct{ state State }
+
+func (d doneStep) Init() tea.Cmd                       { return tea.Quit }
+func (d doneStep) Update(tea.Msg) (tea.Model, tea.Cmd) { return d, tea.Quit }
+func (d doneStep) View() string                        { return "" }
+
+// promptStep is a small reusable single-line prompt used by free-text wizard
+// steps: show a description, collect one line of input via
+// bubbles/textinput, and advance to the next step on Enter. validate, if
+// non-nil, rejects the input (keeping the step on screen with an error
+// message) instead of advancing.
+type promptStep struct {
+	state       State
+	description string
+	input       textinput.Model
+	next        Step
+	apply       func(value string, s *State)
+	validate    func(value string) error
+	err         string
+}
+
+func newPromptStep(state State, description, placeholder string, next Step, apply func(string, *State)) promptStep {
+	return newValidatedPromptStep(state, description, placeholder, next, apply, nil)
+}
+
+func newValidatedPromptStep(state State, description, placeholder string, next Step, apply func(string, *State), validate func(string) error) promptStep {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.Focus()
+	return promptStep{state: state, description: description, input: ti, next: next, apply: apply, validate: validate}
+}
+
+func (p promptStep) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (p promptStep) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			value := p.input.Value()
+			if p.validate != nil {
+				if err := p.validate(value); err != nil {
+					p.err = err.Error()
+					return p, nil
+				}
+			}
+			p.err = ""
+			p.apply(value, &p.state)
+			p.state.Step = p.next
+			return p, func() tea.Msg { return stepDoneMsg{state: p.state} }
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p, tea.Quit
+		}
+	}
+	updated, cmd := p.input.Update(msg)
+	p.input = updated
+	return p, cmd
+}
+
+func (p promptStep) View() string {
+	view := p.description + "\n\n" + p.input.View() + "\n"
+	if p.err != "" {
+		view += "\n! " + p.err + "\n"
+	}
+	return view
+}
+
+// menuOption is a single selectable entry in a menuStep: a value applied to
+// State on selection, a label shown in the list, and an optional
+// description (e.g. hardware requirements) shown under it.
+type menuOption struct {
+	label       string
+	description string
+	value       string
+}
+
+// menuStep is a reusable up/down/enter selection list, used for the
+// client-selection and network-picker steps so they're real menus instead
+// of free-text prompts the user has to already know the valid values for.
+type menuStep struct {
+	state   State
+	title   string
+	options []menuOption
+	cursor  int
+	next    Step
+	apply   func(value string, s *State)
+}
+
+func newMenuStep(state State, title string, options []menuOption, next Step, apply func(string, *State)) menuStep {
+	return menuStep{state: state, title: title, options: options, next: next, apply: apply}
+}
+
+func (m menuStep) Init() tea.Cmd { return nil }
+
+func (m menuStep) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.Type {
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.options)-1 {
+			m.cursor++
+		}
+	case tea.KeyEnter:
+		m.apply(m.options[m.cursor].value, &m.state)
+		m.state.Step = m.next
+		return m, func() tea.Msg { return stepDoneMsg{state: m.state} }
+	case tea.KeyCtrlC, tea.KeyEsc:
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m menuStep) View() string {
+	var b strings.Builder
+	b.WriteString(m.title)
+	b.WriteString("\n\n")
+	for i, opt := range m.options {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		b.WriteString(cursor + opt.label)
+		if opt.description != "" {
+			b.WriteString(" - " + opt.description)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// clientOptions describes the clients offered at each stage of
+// newClientSelectionStep, with the short hardware-requirement blurb shown
+// next to each.
+var clientOptions = map[string][]menuOption{
+	"execution": {
+		{label: "geth", value: "geth", description: "~600GB SSD, lowest memory footprint"},
+		{label: "nethermind", value: "nethermind", description: "~500GB SSD, fast sync, higher memory use"},
+		{label: "besu", value: "besu", description: "~700GB SSD, JVM, enterprise-friendly licensing"},
+		{label: "erigon", value: "erigon", description: "~400GB SSD thanks to flat storage, slower random reads"},
+	},
+	"consensus": {
+		{label: "teku", value: "teku", description: "JVM, heavier memory use, battle-tested slashing protection"},
+		{label: "lighthouse", value: "lighthouse", description: "Rust, low memory, fast checkpoint sync"},
+		{label: "prysm", value: "prysm", description: "Go, most widely run, mature tooling"},
+		{label: "lodestar", value: "lodestar", description: "TypeScript, lightest footprint, newer codebase"},
+		{label: "nimbus", value: "nimbus", description: "Nim, lowest resource usage, good for Raspberry Pi-class hardware"},
+	},
+}
+
+// clientSelectionStep chains three menuSteps (execution, consensus,
+// validator) behind a single StepClientSelection, so quitting mid-wizard
+// and resuming always lands back on "pick the execution client" rather than
+// a sub-step the outer Step enum doesn't know about.
+type clientSelectionStep struct {
+	state State
+	phase int // 0=execution, 1=consensus, 2=validator
+	menu  menuStep
+}
+
+func newClientSelectionStep(state State) tea.Model {
+	s := clientSelectionStep{state: state}
+	s.menu = s.menuFor(0)
+	return s
+}
+
+func (s clientSelectionStep) menuFor(phase int) menuStep {
+	titles := []string{"Select your execution client:", "Select your consensus client:", "Select your validator client:"}
+	kinds := []string{"execution", "consensus", "consensus"} // validator clients are the same binaries as consensus clients
+	return newMenuStep(s.state, titles[phase], clientOptions[kinds[phase]], StepClientSelection, func(string, *State) {})
+}
+
+func (s clientSelectionStep) Init() tea.Cmd { return s.menu.Init() }
+
+func (s clientSelectionStep) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := s.menu.Update(msg)
+	menu, ok := updated.(menuStep)
+	if !ok {
+		return s, cmd
+	}
+	s.menu = menu
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEnter {
+		chosen := menu.options[menu.cursor].value
+		switch s.phase {
+		case 0:
+			s.state.Profile.Execution = chosen
+		case 1:
+			s.state.Profile.Consensus = chosen
+		case 2:
+			s.state.Profile.Validator = chosen
+		}
+		if s.phase < 2 {
+			s.phase++
+			s.menu = s.menuFor(s.phase)
+			return s, s.menu.Init()
+		}
+		s.state.Step = StepNetwork
+		return s, func() tea.Msg { return stepDoneMsg{state: s.state} }
+	}
+	return s, cmd
+}
+
+func (s clientSelectionStep) View() string { return s.menu.View() }
+
+// networkOptions lists every network sedge knows a configs.NetworksConfigs
+// entry for, sorted for a stable menu order.
+func networkOptions() []menuOption {
+	names := make([]string, 0, len(configs.NetworksConfigs))
+	for name := range configs.NetworksConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	opts := make([]menuOption, 0, len(names))
+	for _, name := range names {
+		opts = append(opts, menuOption{label: name, value: name})
+	}
+	return opts
+}
+
+func newNetworkStep(state State) tea.Model {
+	return newMenuStep(state, "Target network:", networkOptions(), StepMev,
+		func(v string, s *State) { s.Profile.Network = v })
+}
+
+func newMevStep(state State) tea.Model {
+	return newMenuStep(state, "Enable mev-boost?", []menuOption{
+		{label: "Yes", value: "yes"},
+		{label: "No", value: "no"},
+	}, StepFeeRecipient, func(v string, s *State) { s.Profile.Mev = v == "yes" })
+}
+
+func newFeeRecipientStep(state State) tea.Model {
+	return newValidatedPromptStep(state, "Fee recipient address (0x followed by 40 hex digits; the EIP-55 checksum is not verified):", "0x...", StepCheckpointSync,
+		func(v string, s *State) { s.Profile.FeeRecipient = v },
+		func(v string) error {
+			if !utils.IsAddress(v) {
+				return fmt.Errorf("%q is not a valid 20-byte Ethereum address", v)
+			}
+			return nil
+		})
+}
+
+func newCheckpointSyncStep(state State) tea.Model {
+	return newPromptStep(state, "Checkpoint sync URL (or 'auto', or blank to skip):", "auto", StepKeystoreImport,
+		func(v string, s *State) { s.Profile.CheckpointSyncUrl = v })
+}
+
+func newKeystoreImportStep(state State) tea.Model {
+	return newPromptStep(state, "Path to existing validator keystores to import (or blank to skip):", "", StepGraffiti,
+		func(v string, s *State) { s.Profile.KeystorePath = v })
+}
+
+func newGraffitiStep(state State) tea.Model {
+	return newPromptStep(state, "Graffiti for the validator client (or blank):", "", StepPortMapping,
+		func(v string, s *State) { s.Profile.Graffiti = v })
+}
+
+// portMappingStep lets the user add zero or more service->port overrides by
+// picking the service from a menu, then typing the port, repeating until
+// they pick "Done" instead of a service.
+type portMappingStep struct {
+	state   State
+	picking menuStep
+	typing  *promptStep
+	service string
+}
+
+// portMappingOptions lists the services a port override can target, plus
+// the sentinel "done" option that ends the step.
+func portMappingOptions() []menuOption {
+	return []menuOption{
+		{label: "execution", value: "execution"},
+		{label: "consensus", value: "consensus"},
+		{label: "validator", value: "validator"},
+		{label: "Done", value: "done"},
+	}
+}
+
+func newPortMappingStep(state State) tea.Model {
+	if state.Profile.Ports == nil {
+		state.Profile.Ports = map[string]string{}
+	}
+	s := portMappingStep{state: state}
+	s.picking = newMenuStep(state, "Add a port mapping override (or select Done):", portMappingOptions(), StepPortMapping, func(string, *State) {})
+	return s
+}
+
+func (s portMappingStep) Init() tea.Cmd { return s.picking.Init() }
+
+func (s portMappingStep) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if s.typing != nil {
+		updated, cmd := s.typing.Update(msg)
+		typing, ok := updated.(promptStep)
+		if !ok {
+			return s, cmd
+		}
+		s.typing = &typing
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEnter && typing.err == "" {
+			s.state.Profile.Ports[s.service] = typing.input.Value()
+			s.typing = nil
+			s.picking = newMenuStep(s.state, "Add another port mapping override (or select Done):", portMappingOptions(), StepPortMapping, func(string, *State) {})
+			return s, s.picking.Init()
+		}
+		return s, cmd
+	}
+
+	updated, cmd := s.picking.Update(msg)
+	picking, ok := updated.(menuStep)
+	if !ok {
+		return s, cmd
+	}
+	s.picking = picking
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.Type == tea.KeyEnter {
+		chosen := picking.options[picking.cursor].value
+		if chosen == "done" {
+			s.state.Step = StepDone
+			return s, func() tea.Msg { return stepDoneMsg{state: s.state} }
+		}
+		s.service = chosen
+		typing := newPromptStep(s.state, fmt.Sprintf("Host port for %s:", chosen), "", StepPortMapping, func(string, *State) {})
+		s.typing = &typing
+		return s, s.typing.Init()
+	}
+	return s, cmd
+}
+
+func (s portMappingStep) View() string {
+	if s.typing != nil {
+		return s.typing.View()
+	}
+	return s.picking.View()
+}
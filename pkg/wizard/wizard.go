@@ -0,0 +1,177 @@
+/*
+Copyright 2022 Nethermind
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wizard implements the interactive, multi-step flow behind
+// `sedge cli wizard`. It is organized the same way pkg/cmd/validator is
+// organized in validatorctl: one model per step, a State that threads
+// between them, and a thin cobra command (cli.WizardCmd, through
+// internal/ui's RunWizard) that just runs the program and hands the result
+// to pkg/profile.
+package wizard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"github.com/NethermindEth/sedge/pkg/profile"
+)
+
+// Step identifies a single screen in the wizard flow. Steps run in this
+// order; State.Step records the next step to show, so a quit mid-way can
+// be resumed from the right place.
+type Step int
+
+const (
+	StepClientSelection Step = iota
+	StepNetwork
+	StepMev
+	StepFeeRecipient
+	StepCheckpointSync
+	StepKeystoreImport
+	StepGraffiti
+	StepPortMapping
+	StepDone
+)
+
+// State is the running answer set, checkpointed to disk after every step so
+// the wizard can resume a quit-mid-way session.
+type State struct {
+	Step    Step            `yaml:"step"`
+	Profile profile.Profile `yaml:"profile"`
+}
+
+// checkpointPath returns where in-progress wizard state is stored: a single
+// well-known file, since only one wizard session can usefully be resumed at
+// a time.
+func checkpointPath() (string, error) {
+	dir, err := profile.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dir), "wizard.checkpoint.yaml"), nil
+}
+
+// SaveCheckpoint persists the in-progress State so it can be resumed later.
+func SaveCheckpoint(s *State) error {
+	path, err := checkpointPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal wizard checkpoint: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadCheckpoint reads a previously saved in-progress State, if any. A
+// missing checkpoint file is not an error: it just means there's nothing
+// to resume.
+func LoadCheckpoint() (*State, error) {
+	path, err := checkpointPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wizard checkpoint: %w", err)
+	}
+	var s State
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse wizard checkpoint: %w", err)
+	}
+	return &s, nil
+}
+
+// ClearCheckpoint removes a completed or abandoned wizard checkpoint.
+func ClearCheckpoint() error {
+	path, err := checkpointPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove wizard checkpoint: %w", err)
+	}
+	return nil
+}
+
+// model is the root bubbletea model. It delegates Update/View to the
+// sub-model for the current step and advances State.Step when a step
+// reports completion via stepDoneMsg.
+type model struct {
+	state   State
+	current tea.Model
+}
+
+// stepDoneMsg is emitted by a step's sub-model once its question has been
+// answered, carrying the updated State forward to the next step.
+type stepDoneMsg struct {
+	state State
+}
+
+// NewProgram builds the bubbletea program for the wizard, resuming from
+// resumeFrom if non-nil.
+func NewProgram(resumeFrom *State) *tea.Program {
+	initial := State{Step: StepClientSelection}
+	if resumeFrom != nil {
+		initial = *resumeFrom
+	}
+	return tea.NewProgram(model{state: initial, current: stepModelFor(initial)})
+}
+
+func (m model) Init() tea.Cmd {
+	return m.current.Init()
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if done, ok := msg.(stepDoneMsg); ok {
+		m.state = done.state
+		if err := SaveCheckpoint(&m.state); err != nil {
+			// Checkpointing failures shouldn't abort the interactive
+			// session; the user just loses resumability for this run.
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		if m.state.Step == StepDone {
+			return m, tea.Quit
+		}
+		m.current = stepModelFor(m.state)
+		return m, m.current.Init()
+	}
+	updated, cmd := m.current.Update(msg)
+	m.current = updated
+	return m, cmd
+}
+
+func (m model) View() string {
+	return m.current.View()
+}
+
+// Result returns the final, fully-answered profile once the program exits
+// with State.Step == StepDone.
+func Result(m tea.Model) (*profile.Profile, bool) {
+	root, ok := m.(model)
+	if !ok || root.state.Step != StepDone {
+		return nil, false
+	}
+	return &root.state.Profile, true
+}